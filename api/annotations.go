@@ -1,6 +1,9 @@
 package api
 
-import "fmt"
+import (
+	"cmp"
+	"slices"
+)
 
 type Annotations []AnnotationData
 
@@ -9,13 +12,106 @@ type AnnotationData struct {
 	Message string `json:"message"`
 }
 
-// Return annotation.Range as readable format MM:SS
+// Return annotation.Range as readable format MM:SS, or H:MM:SS from an
+// hour on.
 func (annotation AnnotationData) GetReadableRange() []string {
-	readableRange := make([]string, len(annotation.Range))
+	readableRange, _ := annotation.FormatRange(TimestampClock)
+	return readableRange
+}
+
+// Returns annotation.Range written in format.
+func (annotation AnnotationData) FormatRange(format TimestampFormat) ([]string, error) {
+	formattedRange := make([]string, len(annotation.Range))
 
 	for i, annotationTime := range annotation.Range {
-		readableRange[i] = fmt.Sprintf("%02d:%02d", annotationTime/60, annotationTime%60)
+		formatted, err := FormatTimestamp(annotationTime, format)
+		if err != nil {
+			return nil, err
+		}
+		formattedRange[i] = formatted
 	}
 
-	return readableRange
+	return formattedRange, nil
+}
+
+// ProcessOptions configures Annotations.Process.
+type ProcessOptions struct {
+	// Merge annotations whose ranges overlap or touch into one, spanning
+	// both ranges, with their messages separated by a blank line.
+	Merge bool
+}
+
+// Returns the annotations sorted by start time, then end time, without
+// exact duplicates. Annotations without a range are kept last, in their
+// original order. The annotations are not modified.
+func (annos Annotations) Process(options ProcessOptions) Annotations {
+	processed := make(Annotations, 0, len(annos))
+	for _, anno := range annos {
+		if !slices.ContainsFunc(processed, anno.Equal) {
+			processed = append(processed, anno)
+		}
+	}
+
+	slices.SortStableFunc(processed, func(a, b AnnotationData) int {
+		if len(a.Range) == 0 || len(b.Range) == 0 {
+			// Annotations without a range go last.
+			return cmp.Compare(len(b.Range), len(a.Range))
+		}
+
+		return cmp.Or(
+			cmp.Compare(a.start(), b.start()),
+			cmp.Compare(a.end(), b.end()),
+		)
+	})
+
+	if !options.Merge {
+		return processed
+	}
+
+	var merged Annotations
+	for _, anno := range processed {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if len(last.Range) > 0 && len(anno.Range) > 0 && anno.start() <= last.end() {
+				*last = last.merge(anno)
+				continue
+			}
+		}
+
+		merged = append(merged, anno)
+	}
+
+	return merged
+}
+
+// Returns whether annotation and other have the same range and message.
+func (annotation AnnotationData) Equal(other AnnotationData) bool {
+	return annotation.Message == other.Message && slices.Equal(annotation.Range, other.Range)
+}
+
+func (annotation AnnotationData) start() int {
+	return annotation.Range[0]
+}
+
+// Returns the end of the range, its start if it has no end.
+func (annotation AnnotationData) end() int {
+	return annotation.Range[len(annotation.Range)-1]
+}
+
+// Returns annotation spanning its range and the range of other, with both
+// messages.
+func (annotation AnnotationData) merge(other AnnotationData) AnnotationData {
+	merged := AnnotationData{
+		Range:   []int{min(annotation.start(), other.start()), max(annotation.end(), other.end())},
+		Message: annotation.Message,
+	}
+
+	if other.Message != "" && other.Message != annotation.Message {
+		if merged.Message != "" {
+			merged.Message += "\n\n"
+		}
+		merged.Message += other.Message
+	}
+
+	return merged
 }
@@ -25,7 +25,7 @@ func TestAnnotationData_GetReadableRange(t *testing.T) {
 		},
 		{
 			Range: []int{3599, 3601},
-			want:  []string{"59:59", "60:01"},
+			want:  []string{"59:59", "1:00:01"},
 		},
 		{
 			Range: []int{125, 130},
@@ -50,3 +50,54 @@ func TestAnnotationData_GetReadableRange(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotations_Process(t *testing.T) {
+	annos := Annotations{
+		{Range: []int{30, 40}, Message: "b"},
+		{Message: "no range"},
+		{Range: []int{10, 35}, Message: "a"},
+		{Range: []int{30, 40}, Message: "b"},
+		{Range: []int{30}, Message: "c"},
+		{Range: []int{50, 60}, Message: "d"},
+	}
+
+	processTests := []struct {
+		name    string
+		options ProcessOptions
+		want    Annotations
+	}{
+		{
+			name: "sorted without duplicates",
+			want: Annotations{
+				{Range: []int{10, 35}, Message: "a"},
+				{Range: []int{30}, Message: "c"},
+				{Range: []int{30, 40}, Message: "b"},
+				{Range: []int{50, 60}, Message: "d"},
+				{Message: "no range"},
+			},
+		},
+		{
+			name:    "merged",
+			options: ProcessOptions{Merge: true},
+			want: Annotations{
+				{Range: []int{10, 40}, Message: "a\n\nc\n\nb"},
+				{Range: []int{50, 60}, Message: "d"},
+				{Message: "no range"},
+			},
+		},
+	}
+
+	for _, c := range processTests {
+		t.Run(c.name, func(t *testing.T) {
+			answer := annos.Process(c.options)
+
+			if !reflect.DeepEqual(answer, c.want) {
+				t.Errorf("got %v, want %v", answer, c.want)
+			}
+		})
+	}
+
+	if annos[0].Message != "b" {
+		t.Error("annotations were modified")
+	}
+}
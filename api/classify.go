@@ -0,0 +1,63 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AnnotationKind is the kind of an annotation, guessed from its message.
+type AnnotationKind string
+
+const (
+	AnnotationNote    AnnotationKind = "note"
+	AnnotationTip     AnnotationKind = "tip"
+	AnnotationWarning AnnotationKind = "warning"
+	AnnotationLink    AnnotationKind = "link"
+	AnnotationCode    AnnotationKind = "code"
+)
+
+var (
+	urlPattern  = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+	codePattern = regexp.MustCompile("`([^`\n]+)`")
+
+	warningPattern = regexp.MustCompile(`(?i)\b(warning|careful|caution|beware|deprecated|breaking|bug|error|typo|mistake|misspoke|incorrect|correction|don't|do not)\b`)
+	tipPattern     = regexp.MustCompile(`(?i)\b(tip|hint|shortcut|you can also|pro tip)\b`)
+)
+
+// Returns the kind of the annotation. Warnings win over tips, tips over
+// code and code over links; anything else is a note.
+func (annotation AnnotationData) Kind() AnnotationKind {
+	switch {
+	case warningPattern.MatchString(annotation.Message):
+		return AnnotationWarning
+	case tipPattern.MatchString(annotation.Message):
+		return AnnotationTip
+	case len(annotation.Code()) > 0:
+		return AnnotationCode
+	case len(annotation.URLs()) > 0:
+		return AnnotationLink
+	default:
+		return AnnotationNote
+	}
+}
+
+// Returns the URLs in the message of the annotation, in order.
+func (annotation AnnotationData) URLs() []string {
+	var urls []string
+	for _, url := range urlPattern.FindAllString(annotation.Message, -1) {
+		urls = append(urls, strings.TrimRight(url, ".,;:!?"))
+	}
+
+	return urls
+}
+
+// Returns the inline code spans in the message of the annotation, in
+// order, without their backticks.
+func (annotation AnnotationData) Code() []string {
+	var code []string
+	for _, match := range codePattern.FindAllStringSubmatch(annotation.Message, -1) {
+		code = append(code, match[1])
+	}
+
+	return code
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotationData_Kind(t *testing.T) {
+	kindTests := []struct {
+		message string
+		want    AnnotationKind
+	}{
+		{"The slides are on the course page.", AnnotationNote},
+		{"Careful, this API is deprecated.", AnnotationWarning},
+		{"I misspoke, it is O(n).", AnnotationWarning},
+		{"Don't forget to `return`.", AnnotationWarning},
+		{"Pro tip: `go vet` catches this.", AnnotationTip},
+		{"Run `npm install` first.", AnnotationCode},
+		{"See https://go.dev/doc for more.", AnnotationLink},
+		{"The tipping point.", AnnotationNote},
+	}
+
+	for _, c := range kindTests {
+		t.Run(c.message, func(t *testing.T) {
+			answer := AnnotationData{Message: c.message}.Kind()
+
+			if answer != c.want {
+				t.Errorf("got %v, want %v", answer, c.want)
+			}
+		})
+	}
+}
+
+func TestAnnotationData_URLsAndCode(t *testing.T) {
+	anno := AnnotationData{
+		Message: "Use `fetch()` (see https://developer.mozilla.org/docs/Web/API/fetch), or `axios`: http://axios-http.com.",
+	}
+
+	wantURLs := []string{"https://developer.mozilla.org/docs/Web/API/fetch", "http://axios-http.com"}
+	if urls := anno.URLs(); !reflect.DeepEqual(urls, wantURLs) {
+		t.Errorf("got %v, want %v", urls, wantURLs)
+	}
+
+	wantCode := []string{"fetch()", "axios"}
+	if code := anno.Code(); !reflect.DeepEqual(code, wantCode) {
+		t.Errorf("got %v, want %v", code, wantCode)
+	}
+}
@@ -20,14 +20,30 @@ const (
 
 type CourseData struct {
 	Slug          string `json:"slug"`
-	Title         string `json:"Title"`
-	DatePublished string `json:"datePublished"`
-	Description   string `json:"description"`
-	LessonsHash   []string
+	Title         string `json:"title"`
+	DatePublished string `json:"datePublished" schema:"optional"`
+	Description   string `json:"description" schema:"optional"`
+	LessonsHash   map[int]string
 
-	Sections Sections
+	Sections Sections `json:"lessonElements"`
 
 	Lessons lessons `json:"lessonData"`
+
+	// DatePublished parsed, zero if it can not be parsed.
+	Published time.Time `json:"-"`
+}
+
+func (course *CourseData) UnmarshalJSON(data []byte) error {
+	type courseJSON CourseData
+	if err := json.Unmarshal(data, (*courseJSON)(course)); err != nil {
+		return err
+	}
+
+	if course.DatePublished != "" {
+		course.Published, _ = ParseDate(course.DatePublished)
+	}
+
+	return nil
 }
 
 func NewCourse(slug string) (CourseData, error) {
@@ -37,7 +53,7 @@ func NewCourse(slug string) (CourseData, error) {
 
 	err := course.fetchAndPopulateJSON()
 	if err != nil {
-		return CourseData{}, nil
+		return CourseData{}, err
 	}
 
 	course.populateLessonsHash()
@@ -46,8 +62,10 @@ func NewCourse(slug string) (CourseData, error) {
 }
 
 // Populates course.LessonsHash by lesson hash by index of each lesson.
+// The indexes come from the API unchecked, so they are map keys rather
+// than slice positions, see CourseData.Validate.
 func (course *CourseData) populateLessonsHash() {
-	course.LessonsHash = make([]string, course.lessonsCount())
+	course.LessonsHash = make(map[int]string, len(course.Lessons))
 
 	for lessonHash, lesson := range course.Lessons {
 		course.LessonsHash[lesson.Index] = lessonHash
@@ -61,19 +79,10 @@ func (course *CourseData) fetchAndPopulateJSON() error {
 		return err
 	}
 
-	err = json.Unmarshal(requestBody, &course)
-	if err != nil {
-		return err
+	if err := json.Unmarshal(requestBody, course); err != nil {
+		return fmt.Errorf("decoding course %s: %w", course.Slug, err)
 	}
 
-	// Unmarshalling Sections
-	var rawSections rawSectionsJSON
-	if err := json.Unmarshal(requestBody, &rawSections); err != nil {
-		return err
-	}
-
-	course.Sections = rawSections.toLessonElements()
-
 	return nil
 }
 
@@ -88,7 +97,7 @@ func (course *CourseData) fetch() ([]byte, error) {
 		return nil, err
 	}
 
-	if _, err := course.addToCache(body); err != nil {
+	if _, err := course.addToCache(body); err != nil && !errors.Is(err, cache.ErrDisabled) {
 		return nil, err
 	}
 	return body, nil
@@ -143,33 +152,27 @@ func (course *CourseData) addToCache(data []byte) (int, error) {
 }
 
 // Returns the lesson count of the course.
-// Needs to be used after course.fetchJson(), otherwise
-// the return value is -1.
 func (course *CourseData) lessonsCount() int {
-	if len(course.Sections) == 0 {
-		return -1
-	}
-
-	courseSectionsLen := len(course.Sections)
-	lastSection := course.Sections[courseSectionsLen-1]
-	return lastSection.LessonsIndex[len(lastSection.LessonsIndex)-1] + 1
+	return len(course.Lessons)
 }
 
-// Returns the course Duration.
-func (course *CourseData) courseDuration() (time.Duration, error) {
-	duration := 0 * time.Second
-
+// Returns the course duration, the sum of its section durations. If the
+// sections have no durations, the lesson durations are summed instead.
+func (course CourseData) Duration() time.Duration {
+	var duration time.Duration
 	for _, section := range course.Sections {
+		duration += section.Duration
+	}
 
-		currentDur, err := time.ParseDuration(strings.ReplaceAll(section.Duration, " ", ""))
-		if err != nil {
-			return time.Duration(0), fmt.Errorf("error while parsing section Duration: %w", err)
-		}
+	if duration > 0 {
+		return duration
+	}
 
-		duration += currentDur
+	for _, lesson := range course.Lessons {
+		duration += lesson.Duration
 	}
 
-	return duration, nil
+	return duration
 }
 
 func (course CourseData) String() string {
@@ -180,11 +183,9 @@ func (course CourseData) String() string {
 	result.WriteString(fmt.Sprintf("\tDescription: %v\n", course.Description))
 	result.WriteString(fmt.Sprintf("\tNumber of Lessons: %d\n", course.lessonsCount()))
 
-	duration, err := course.courseDuration()
-	if err != nil {
-		result.WriteString("\tDuration: Failed to parse Duration.")
-	} else {
-		result.WriteString(fmt.Sprintf("\tDuration: %v\n", duration))
+	result.WriteString(fmt.Sprintf("\tDuration: %v\n", course.Duration()))
+	if !course.Published.IsZero() {
+		result.WriteString(fmt.Sprintf("\tPublished: %s\n", course.Published.Format(time.DateOnly)))
 	}
 
 	return result.String()
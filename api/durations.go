@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var isoDurationRegexp = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// Parses the durations the API emits, such as "1h 5m 24s", and also
+// accepts clock durations ("1:05:24", "05:24"), ISO-8601 durations
+// ("PT1H5M24S") and plain seconds ("3924").
+func ParseDuration(duration string) (time.Duration, error) {
+	duration = strings.TrimSpace(duration)
+
+	switch {
+	case duration == "":
+		return 0, fmt.Errorf("empty duration")
+	case strings.HasPrefix(duration, "P"):
+		return parseISODuration(duration)
+	case strings.Contains(duration, ":"):
+		return parseClock(duration)
+	}
+
+	if seconds, err := strconv.ParseFloat(duration, 64); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("negative duration %q", duration)
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	parsed, err := time.ParseDuration(strings.ReplaceAll(duration, " ", ""))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", duration)
+	}
+
+	if parsed < 0 {
+		return 0, fmt.Errorf("negative duration %q", duration)
+	}
+
+	return parsed, nil
+}
+
+// Parses lesson timestamps, the start and end offsets of a lesson in the
+// course, such as "00:05:12 - 00:09:51". A timestamp without an end only
+// has a start, and end is returned as zero.
+func ParseTimestamp(timestamp string) (start, end time.Duration, err error) {
+	startText, endText, hasEnd := strings.Cut(strings.ReplaceAll(timestamp, "–", "-"), " - ")
+	if !hasEnd {
+		startText, endText, hasEnd = strings.Cut(startText, "-")
+	}
+
+	start, err = parseClock(startText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid timestamp %q: %w", timestamp, err)
+	}
+
+	if !hasEnd {
+		return start, 0, nil
+	}
+
+	end, err = parseClock(endText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid timestamp %q: %w", timestamp, err)
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid timestamp %q: ends before it starts", timestamp)
+	}
+
+	return start, end, nil
+}
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// Parses publish dates, as RFC 3339 timestamps, plain dates or dates
+// written out in English.
+func ParseDate(date string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", date)
+}
+
+// Parses clock durations such as "1:05:24", "05:24" or "05:24.5".
+func parseClock(clock string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(clock), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid clock duration %q", clock)
+	}
+
+	var seconds float64
+	for x, part := range parts {
+		var (
+			value float64
+			err   error
+		)
+		if x == len(parts)-1 {
+			value, err = strconv.ParseFloat(part, 64)
+		} else {
+			var integer int
+			integer, err = strconv.Atoi(part)
+			value = float64(integer)
+		}
+
+		if err != nil || value < 0 || (x > 0 && value >= 60) {
+			return 0, fmt.Errorf("invalid clock duration %q", clock)
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// Parses ISO-8601 durations such as "PT1H5M24S".
+func parseISODuration(duration string) (time.Duration, error) {
+	matches := isoDurationRegexp.FindStringSubmatch(duration)
+	if matches == nil || duration == "P" || strings.HasSuffix(duration, "T") {
+		return 0, fmt.Errorf("invalid duration %q", duration)
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+	var result time.Duration
+	for x, unit := range units {
+		if matches[x+1] == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(matches[x+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", duration)
+		}
+
+		result += time.Duration(value * float64(unit))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	durationTests := []struct {
+		duration string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{"1h 5m 24s", time.Hour + 5*time.Minute + 24*time.Second, false},
+		{"5m", 5 * time.Minute, false},
+		{"42s", 42 * time.Second, false},
+		{"1:05:24", time.Hour + 5*time.Minute + 24*time.Second, false},
+		{"05:24", 5*time.Minute + 24*time.Second, false},
+		{"PT1H5M24S", time.Hour + 5*time.Minute + 24*time.Second, false},
+		{"PT90S", 90 * time.Second, false},
+		{"3924", time.Hour + 5*time.Minute + 24*time.Second, false},
+		{"", 0, true},
+		{"forever", 0, true},
+		{"-5m", 0, true},
+		{"05:75", 0, true},
+		{"PT", 0, true},
+	}
+
+	for _, c := range durationTests {
+		t.Run(c.duration, func(t *testing.T) {
+			answer, err := ParseDuration(c.duration)
+
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+
+			if answer != c.want {
+				t.Errorf("got %v, want %v", answer, c.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	timestampTests := []struct {
+		timestamp string
+		wantStart time.Duration
+		wantEnd   time.Duration
+		wantErr   bool
+	}{
+		{"00:00:00 - 00:05:12", 0, 5*time.Minute + 12*time.Second, false},
+		{"01:02:03 - 01:10:00", time.Hour + 2*time.Minute + 3*time.Second, time.Hour + 10*time.Minute, false},
+		{"5:12-9:51", 5*time.Minute + 12*time.Second, 9*time.Minute + 51*time.Second, false},
+		{"00:05:12", 5*time.Minute + 12*time.Second, 0, false},
+		{"00:09:00 - 00:05:00", 0, 0, true},
+		{"soon", 0, 0, true},
+	}
+
+	for _, c := range timestampTests {
+		t.Run(c.timestamp, func(t *testing.T) {
+			start, end, err := ParseTimestamp(c.timestamp)
+
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+
+			if start != c.wantStart || end != c.wantEnd {
+				t.Errorf("got %v - %v, want %v - %v", start, end, c.wantStart, c.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2023, time.January, 17, 0, 0, 0, 0, time.UTC)
+
+	for _, date := range []string{"2023-01-17T00:00:00.000Z", "2023-01-17", "January 17, 2023", "Jan 17, 2023"} {
+		t.Run(date, func(t *testing.T) {
+			answer, err := ParseDate(date)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !answer.Equal(want) {
+				t.Errorf("got %v, want %v", answer, want)
+			}
+		})
+	}
+}
+
+func TestLessonData_UnmarshalJSON(t *testing.T) {
+	var lesson LessonData
+	if err := lesson.UnmarshalJSON([]byte(`{"slug": "setup", "timestamp": "00:05:00 - 00:07:30"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if lesson.Start != 5*time.Minute || lesson.Duration != 2*time.Minute+30*time.Second {
+		t.Errorf("got start %v and duration %v, want 5m0s and 2m30s", lesson.Start, lesson.Duration)
+	}
+}
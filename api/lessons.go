@@ -1,12 +1,38 @@
 package api
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type lessons map[string]LessonData
 
 type LessonData struct {
 	Slug        string      `json:"slug"`
-	Title       string      `json:"Title"`
-	Description string      `json:"description"`
+	Title       string      `json:"title"`
+	Description string      `json:"description" schema:"optional"`
 	Index       int         `json:"index"`
-	Timestamp   string      `json:"timestamp"`
-	Annotations Annotations `json:"annotations"`
+	Timestamp   string      `json:"timestamp" schema:"optional"`
+	Annotations Annotations `json:"annotations" schema:"optional"`
+
+	// Offset of the lesson in the course, and its duration, parsed from
+	// Timestamp. Both are zero if Timestamp can not be parsed.
+	Start    time.Duration `json:"-"`
+	Duration time.Duration `json:"-"`
+}
+
+func (lesson *LessonData) UnmarshalJSON(data []byte) error {
+	type lessonJSON LessonData
+	if err := json.Unmarshal(data, (*lessonJSON)(lesson)); err != nil {
+		return err
+	}
+
+	if start, end, err := ParseTimestamp(lesson.Timestamp); err == nil {
+		lesson.Start = start
+		if end > 0 {
+			lesson.Duration = end - start
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SchemaField is a field path of the course payload, such as
+// "lessonData.*.title", with the number of objects it applies to.
+type SchemaField struct {
+	Path string
+	// Number of objects at the parent path with this problem.
+	Count int
+	// Number of objects at the parent path.
+	Total int
+	// For case mismatches, the key as it is in the payload.
+	Got string
+}
+
+func (field SchemaField) String() string {
+	result := field.Path
+	if field.Got != "" {
+		result += fmt.Sprintf(" (payload has %q)", field.Got)
+	}
+
+	return result + fmt.Sprintf(" in %d of %d object(s)", field.Count, field.Total)
+}
+
+// SchemaReport compares a course payload against the model it is decoded
+// into.
+//
+// Fields tagged with schema:"optional" in the model are never reported as
+// missing. Checking decodes the payload once more, so it is only done on
+// request, see CheckSchema.
+type SchemaReport struct {
+	// Fields in the payload that the model does not decode.
+	Unknown []SchemaField
+	// Required fields of the model that are not in the payload.
+	Missing []SchemaField
+	// Fields that only match the model case-insensitively. They are still
+	// decoded, but are a sign that the API changed.
+	CaseMismatch []SchemaField
+}
+
+// Returns true if the payload lost or renamed fields the model expects.
+// Unknown fields are not drift, the API has many fields we do not use.
+func (report SchemaReport) Drift() bool {
+	return len(report.Missing) > 0 || len(report.CaseMismatch) > 0
+}
+
+func (report SchemaReport) String() string {
+	var result strings.Builder
+
+	if !report.Drift() && len(report.Unknown) == 0 {
+		return "payload matches the expected model.\n"
+	}
+
+	for _, group := range []struct {
+		name   string
+		fields []SchemaField
+	}{
+		{"missing", report.Missing},
+		{"case mismatch", report.CaseMismatch},
+		{"unknown", report.Unknown},
+	} {
+		for _, field := range group.fields {
+			result.WriteString(fmt.Sprintf("\t%s: %s\n", group.name, field))
+		}
+	}
+
+	return result.String()
+}
+
+// Checks payload against the CourseData model.
+func CheckSchema(payload []byte) (SchemaReport, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return SchemaReport{}, err
+	}
+
+	checker := schemaChecker{
+		totals:       make(map[string]int),
+		unknown:      make(map[string]int),
+		missing:      make(map[string]int),
+		caseMismatch: make(map[string]map[string]int),
+	}
+	checker.check("", value, reflect.TypeFor[CourseData]())
+
+	return checker.report(), nil
+}
+
+// Fetches the payload of the course slug, and checks it against the
+// CourseData model.
+func CheckCourseSchema(slug string) (SchemaReport, error) {
+	course := CourseData{Slug: slug}
+
+	payload, err := course.fetch()
+	if err != nil {
+		return SchemaReport{}, err
+	}
+
+	return CheckSchema(payload)
+}
+
+type schemaChecker struct {
+	// Number of objects seen by path.
+	totals map[string]int
+	// Counts by field path.
+	unknown      map[string]int
+	missing      map[string]int
+	caseMismatch map[string]map[string]int
+}
+
+var sectionsType = reflect.TypeFor[Sections]()
+
+func (checker *schemaChecker) check(path string, value any, typ reflect.Type) {
+	switch {
+	case typ == sectionsType:
+		elements, _ := value.([]any)
+		for _, element := range elements {
+			if _, ok := element.(map[string]any); ok {
+				checker.check(joinPath(path, "[]"), element, reflect.TypeFor[sectionElement]())
+			}
+		}
+	case typ.Kind() == reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		checker.checkObject(path, object, typ)
+	case typ.Kind() == reflect.Slice:
+		elements, _ := value.([]any)
+		for _, element := range elements {
+			checker.check(joinPath(path, "[]"), element, typ.Elem())
+		}
+	case typ.Kind() == reflect.Map:
+		object, _ := value.(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(object)) {
+			checker.check(joinPath(path, "*"), object[key], typ.Elem())
+		}
+	}
+}
+
+func (checker *schemaChecker) checkObject(path string, object map[string]any, typ reflect.Type) {
+	checker.totals[path]++
+	matched := make(map[string]bool)
+
+	for x := range typ.NumField() {
+		field := typ.Field(x)
+		tag, ok := field.Tag.Lookup("json")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		fieldPath := joinPath(path, name)
+
+		key, found := name, false
+		if _, found = object[name]; !found {
+			for payloadKey := range object {
+				if strings.EqualFold(payloadKey, name) {
+					key, found = payloadKey, true
+
+					if checker.caseMismatch[fieldPath] == nil {
+						checker.caseMismatch[fieldPath] = make(map[string]int)
+					}
+					checker.caseMismatch[fieldPath][payloadKey]++
+					break
+				}
+			}
+		}
+
+		if !found {
+			if field.Tag.Get("schema") != "optional" {
+				checker.missing[fieldPath]++
+			}
+			continue
+		}
+
+		matched[key] = true
+		checker.check(fieldPath, object[key], field.Type)
+	}
+
+	for key := range object {
+		if !matched[key] {
+			checker.unknown[joinPath(path, key)]++
+		}
+	}
+}
+
+func (checker *schemaChecker) report() SchemaReport {
+	var report SchemaReport
+
+	for _, fieldPath := range slices.Sorted(maps.Keys(checker.unknown)) {
+		report.Unknown = append(report.Unknown, checker.field(fieldPath, checker.unknown[fieldPath], ""))
+	}
+
+	for _, fieldPath := range slices.Sorted(maps.Keys(checker.missing)) {
+		report.Missing = append(report.Missing, checker.field(fieldPath, checker.missing[fieldPath], ""))
+	}
+
+	for _, fieldPath := range slices.Sorted(maps.Keys(checker.caseMismatch)) {
+		for _, got := range slices.Sorted(maps.Keys(checker.caseMismatch[fieldPath])) {
+			report.CaseMismatch = append(report.CaseMismatch, checker.field(fieldPath, checker.caseMismatch[fieldPath][got], got))
+		}
+	}
+
+	return report
+}
+
+func (checker *schemaChecker) field(fieldPath string, count int, got string) SchemaField {
+	parent := ""
+	if x := strings.LastIndexByte(fieldPath, '.'); x >= 0 {
+		parent = fieldPath[:x]
+	}
+
+	return SchemaField{
+		Path:  fieldPath,
+		Count: count,
+		Total: checker.totals[parent],
+		Got:   got,
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	payload := `{
+		"slug": "go-basics",
+		"Title": "Go Basics",
+		"instructors": [],
+		"lessonElements": [{"title": "Intro", "duration": "5m", "icon": "x"}, 0, 1],
+		"lessonData": {
+			"a": {"slug": "welcome", "title": "Welcome", "index": 0, "annotations": [{"range": [1, 2]}]},
+			"b": {"title": "Setup", "index": 1}
+		}
+	}`
+
+	report, err := CheckSchema([]byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := SchemaReport{
+		Unknown: []SchemaField{
+			{Path: "instructors", Count: 1, Total: 1},
+			{Path: "lessonElements.[].icon", Count: 1, Total: 1},
+		},
+		Missing: []SchemaField{
+			{Path: "lessonData.*.annotations.[].message", Count: 1, Total: 1},
+			{Path: "lessonData.*.slug", Count: 1, Total: 2},
+		},
+		CaseMismatch: []SchemaField{
+			{Path: "title", Count: 1, Total: 1, Got: "Title"},
+		},
+	}
+
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %+v, want %+v", report, want)
+	}
+
+	if !report.Drift() {
+		t.Error("got no drift, want drift")
+	}
+}
+
+func TestCheckSchema_NoDrift(t *testing.T) {
+	payload := `{
+		"slug": "go-basics",
+		"title": "Go Basics",
+		"lessonElements": [{"title": "Intro"}, 0],
+		"lessonData": {"a": {"slug": "welcome", "title": "Welcome", "index": 0}}
+	}`
+
+	report, err := CheckSchema([]byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Drift() || len(report.Unknown) > 0 {
+		t.Errorf("got %+v, want an empty report", report)
+	}
+}
@@ -1,124 +1,128 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
-	"strings"
-	"sync"
-	"unicode"
+	"errors"
+	"fmt"
+	"time"
 )
 
 type Sections []SectionData
 
-func newSection(title, duration string, lessonsIndexes []int) SectionData {
-	return SectionData{
+// Returns a new section. Durations that can not be parsed are left as
+// zero, see CourseData.Validate.
+func newSection(title, rawDuration string, lessonsIndexes []int) SectionData {
+	section := SectionData{
 		Title:        title,
-		Duration:     duration,
+		RawDuration:  rawDuration,
 		LessonsIndex: lessonsIndexes,
 	}
+
+	if rawDuration != "" {
+		section.Duration, _ = ParseDuration(rawDuration)
+	}
+
+	return section
 }
 
 type SectionData struct {
-	Title        string
-	Duration     string
+	Title    string
+	Duration time.Duration
+	// Duration as sent by the API, such as "1h 5m 24s".
+	RawDuration  string
 	LessonsIndex []int
 }
 
-// Initialize a map that is used by createSectionDir,
-// to "sanitize" the sectionSlug before creating it.
-var charsMap map[rune]string = make(map[rune]string)
+// LessonElementError is returned when an element of lessonElements does not
+// have the expected shape.
+type LessonElementError struct {
+	Index    int
+	Expected string
+	Got      string
+}
 
-func init() {
-	channel := make(chan uint16)
-	var wg sync.WaitGroup
+func (err *LessonElementError) Error() string {
+	return fmt.Sprintf("lessonElements[%d]: expected %s, got %s", err.Index, err.Expected, err.Got)
+}
 
-	unicodeSlice := [][]unicode.Range16{
-		unicode.S.R16,
-		unicode.P.R16,
-	}
+type sectionElement struct {
+	Title    *string `json:"title"`
+	Duration string  `json:"duration" schema:"optional"`
+}
 
-	wg.Add(len(unicodeSlice))
-	for _, un := range unicodeSlice {
-		go func(un []unicode.Range16) {
-			defer wg.Done()
-			asciiUnicode16(un, channel)
-		}(un)
+// Decodes the lessonElements of the API, a flat list of section objects
+// each followed by the indexes of its lessons:
+//
+//	[{"title": "Introduction", "duration": "5m 10s"}, 0, 1, {"title": ...}, 2]
+func (sections *Sections) UnmarshalJSON(data []byte) error {
+	if kind := jsonKind(data); kind != "array" {
+		return fmt.Errorf("lessonElements: expected array, got %s", kind)
 	}
 
-	go func() {
-		wg.Wait()
-		close(channel)
-	}()
-
-	for char := range channel {
-		charMap := ""
-		switch rune(char) {
-		case '&':
-			charMap = "and"
-		case '-':
-			charMap = "-"
-		case '(', ')', '[', ']', '/', '\\', '|':
-			charMap = "_"
-		}
-
-		charsMap[rune(char)] = charMap
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return fmt.Errorf("lessonElements: %w", err)
 	}
 
-	charsMap[' '] = "-"
-}
-
-func asciiUnicode16(ranges []unicode.Range16, channel chan uint16) {
-	for _, r := range ranges {
-		if r.Lo > unicode.MaxASCII {
-			break
-		}
+	secs := Sections{}
+	for x, element := range elements {
+		switch kind := jsonKind(element); kind {
+		case "object":
+			var section sectionElement
+			if err := json.Unmarshal(element, &section); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if errors.As(err, &typeErr) {
+					return &LessonElementError{x, fmt.Sprintf("%s to be a %s", typeErr.Field, typeErr.Type), typeErr.Value}
+				}
+				return fmt.Errorf("lessonElements[%d]: %w", x, err)
+			}
 
-		for char := r.Lo; char <= r.Hi && char <= unicode.MaxASCII; char += r.Stride {
-			channel <- char
-		}
-	}
-}
+			if section.Title == nil {
+				return &LessonElementError{x, "section object with a title", "section object without a title"}
+			}
 
-func (section SectionData) SlugifiedSectionTitle() string {
-	sectionTitle := strings.Trim(section.Title, " ")
+			secs = append(secs, newSection(*section.Title, section.Duration, []int{}))
+		case "number":
+			var lessonIndex int
+			if err := json.Unmarshal(element, &lessonIndex); err != nil || lessonIndex < 0 {
+				return &LessonElementError{x, "non-negative integer lesson index", string(element)}
+			}
 
-	var slugifiedTitle strings.Builder
+			if len(secs) == 0 {
+				return &LessonElementError{x, "section object before the first lesson index", "lesson index"}
+			}
 
-	for _, char := range sectionTitle {
-		if mappedValue, ok := charsMap[char]; ok {
-			slugifiedTitle.WriteString(mappedValue)
-			continue
+			lastSection := &secs[len(secs)-1]
+			lastSection.LessonsIndex = append(lastSection.LessonsIndex, lessonIndex)
+		default:
+			return &LessonElementError{x, "section object or lesson index", kind}
 		}
-
-		slugifiedTitle.WriteRune(char)
 	}
 
-	return strings.ToLower(slugifiedTitle.String())
-}
-
-type rawSectionsJSON struct {
-	RawJSON []json.RawMessage `json:"lessonElements"`
+	*sections = secs
+	return nil
 }
 
-func (r *rawSectionsJSON) toLessonElements() Sections {
-	var secs Sections
-	var currentSection *SectionData
-
-	for _, jsonElement := range r.RawJSON {
-		var jsonObject map[string]any
-
-		if err := json.Unmarshal(jsonElement, &jsonObject); err == nil {
-			section := newSection(jsonObject["title"].(string), jsonObject["duration"].(string), []int{})
-
-			secs = append(secs, section)
-			currentSection = &secs[len(secs)-1]
-		} else {
-			var num int
+// Returns the kind of JSON value of data.
+func jsonKind(data json.RawMessage) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return "nothing"
+	}
 
-			if err := json.Unmarshal(jsonElement, &num); err == nil && currentSection != nil {
-				currentSection.LessonsIndex = append(currentSection.LessonsIndex, num)
-			}
-		}
+	switch trimmed[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
 	}
 
-	return secs
+	return "number"
 }
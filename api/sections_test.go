@@ -1,32 +1,50 @@
 package api
 
-import "testing"
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
 
-func TestSectionData_SlugifiedSectionTitle(t *testing.T) {
-	sectionsTests := []struct {
-		sectionSlug string
-		want        string
+func TestSections_UnmarshalJSON(t *testing.T) {
+	data := `[{"title": "Introduction", "duration": "5m 10s"}, 0, 1, {"title": "Wrapping Up"}, 2]`
+
+	var sections Sections
+	if err := json.Unmarshal([]byte(data), &sections); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Sections{
+		{Title: "Introduction", RawDuration: "5m 10s", Duration: 5*time.Minute + 10*time.Second, LessonsIndex: []int{0, 1}},
+		{Title: "Wrapping Up", LessonsIndex: []int{2}},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("got %+v, want %+v", sections, want)
+	}
+}
+
+func TestSections_UnmarshalJSONErrors(t *testing.T) {
+	errorTests := []struct {
+		data string
+		want string
 	}{
-		{"Portfolio Footer & Projects", "portfolio-footer-and-projects"},
-		{"Adding a Light/Dark Theme Switcher", "adding-a-light_dark-theme-switcher"},
-		{"Adding a Light|Dark Theme Switcher", "adding-a-light_dark-theme-switcher"},
-		{"Adding a Light\\Dark Theme Switcher", "adding-a-light_dark-theme-switcher"},
-		{"Wrapping up", "wrapping-up"},
-		{"Routing Q&A", "routing-qanda"},
-		{"Protecting Client-Side Routes", "protecting-client-side-routes"},
-		{" Scaffolding an API Project ", "scaffolding-an-api-project"},
-		{"Search, Filter, & Sort", "search-filter-and-sort"},
+		{`{}`, "lessonElements: expected array, got object"},
+		{`[{"title": "Intro"}, "0"]`, "lessonElements[1]: expected section object or lesson index, got string"},
+		{`[{"title": "Intro"}, -1]`, "lessonElements[1]: expected non-negative integer lesson index, got -1"},
+		{`[{"title": "Intro"}, 1.5]`, "lessonElements[1]: expected non-negative integer lesson index, got 1.5"},
+		{`[{"duration": "5m"}]`, "lessonElements[0]: expected section object with a title, got section object without a title"},
+		{`[{"title": 12}]`, "lessonElements[0]: expected title to be a string, got number"},
+		{`[0, {"title": "Intro"}]`, "lessonElements[0]: expected section object before the first lesson index, got lesson index"},
 	}
 
-	for _, c := range sectionsTests {
-		testName := c.sectionSlug
-		t.Run(testName, func(t *testing.T) {
-			answer := SectionData{
-				Title: c.sectionSlug,
-			}.SlugifiedSectionTitle()
+	for _, c := range errorTests {
+		t.Run(c.data, func(t *testing.T) {
+			var sections Sections
+			err := json.Unmarshal([]byte(c.data), &sections)
 
-			if answer != c.want {
-				t.Errorf("got %s, want %s", answer, c.want)
+			if err == nil || err.Error() != c.want {
+				t.Errorf("got %v, want %s", err, c.want)
 			}
 		})
 	}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TimestampFormat is a way of writing a video time given in seconds.
+type TimestampFormat string
+
+const (
+	// MM:SS, or H:MM:SS from an hour on.
+	TimestampClock TimestampFormat = "clock"
+	// H:MM:SS.
+	TimestampHMS TimestampFormat = "hms"
+	// MM:SS, minutes going past 59.
+	TimestampMS TimestampFormat = "ms"
+	// ISO 8601 duration, such as PT1H2M3S.
+	TimestampISO TimestampFormat = "iso"
+	// Number of seconds.
+	TimestampSeconds TimestampFormat = "seconds"
+)
+
+var TimestampFormats = []TimestampFormat{
+	TimestampClock,
+	TimestampHMS,
+	TimestampMS,
+	TimestampISO,
+	TimestampSeconds,
+}
+
+// Returns an error if format is not one of TimestampFormats.
+func (format TimestampFormat) Validate() error {
+	if !slices.Contains(TimestampFormats, format) {
+		names := make([]string, len(TimestampFormats))
+		for x, known := range TimestampFormats {
+			names[x] = string(known)
+		}
+
+		return fmt.Errorf("unknown timestamp format %q, expected one of: %s", format, strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// Returns seconds written in format. Negative times keep their sign
+// in front, such as -00:05.
+func FormatTimestamp(seconds int, format TimestampFormat) (string, error) {
+	if err := format.Validate(); err != nil {
+		return "", err
+	}
+
+	if format == TimestampSeconds {
+		return strconv.Itoa(seconds), nil
+	}
+
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	hours, minutes, secs := seconds/3600, seconds/60%60, seconds%60
+
+	switch format {
+	case TimestampHMS:
+		return fmt.Sprintf("%s%d:%02d:%02d", sign, hours, minutes, secs), nil
+	case TimestampMS:
+		return fmt.Sprintf("%s%02d:%02d", sign, seconds/60, secs), nil
+	case TimestampISO:
+		if seconds == 0 {
+			return "PT0S", nil
+		}
+
+		var result strings.Builder
+		result.WriteString(sign + "PT")
+		for _, part := range []struct {
+			value int
+			unit  string
+		}{{hours, "H"}, {minutes, "M"}, {secs, "S"}} {
+			if part.value > 0 {
+				result.WriteString(strconv.Itoa(part.value) + part.unit)
+			}
+		}
+		return result.String(), nil
+	}
+
+	if hours > 0 {
+		return fmt.Sprintf("%s%d:%02d:%02d", sign, hours, minutes, secs), nil
+	}
+
+	return fmt.Sprintf("%s%02d:%02d", sign, minutes, secs), nil
+}
+
+var (
+	ErrEmptyRange = errors.New("empty range")
+	ErrNoRangeEnd = errors.New("range has a start but no end")
+)
+
+// Checks that the range of annotation is a start and an end, neither of
+// them negative, the end not before the start.
+func (annotation AnnotationData) ValidateRange() error {
+	switch len(annotation.Range) {
+	case 0:
+		return ErrEmptyRange
+	case 1, 2:
+	default:
+		return fmt.Errorf("range has %d values, expected a start and an end", len(annotation.Range))
+	}
+
+	for _, seconds := range annotation.Range {
+		if seconds < 0 {
+			return fmt.Errorf("range has the negative time %d", seconds)
+		}
+	}
+
+	if len(annotation.Range) == 1 {
+		return ErrNoRangeEnd
+	}
+
+	if start, end := annotation.Range[0], annotation.Range[1]; end < start {
+		return fmt.Errorf("range ends at %d, before it starts at %d", end, start)
+	}
+
+	return nil
+}
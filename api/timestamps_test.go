@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	formatTests := []struct {
+		seconds int
+		format  TimestampFormat
+		want    string
+	}{
+		{65, TimestampClock, "01:05"},
+		{3601, TimestampClock, "1:00:01"},
+		{65, TimestampHMS, "0:01:05"},
+		{3601, TimestampMS, "60:01"},
+		{3723, TimestampISO, "PT1H2M3S"},
+		{60, TimestampISO, "PT1M"},
+		{0, TimestampISO, "PT0S"},
+		{3601, TimestampSeconds, "3601"},
+		{-5, TimestampClock, "-00:05"},
+		{-5, TimestampISO, "-PT5S"},
+	}
+
+	for _, c := range formatTests {
+		t.Run(string(c.format)+"/"+c.want, func(t *testing.T) {
+			got, err := FormatTimestamp(c.seconds, c.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+
+	if _, err := FormatTimestamp(1, "minutes"); err == nil {
+		t.Error("got no error for an unknown format")
+	}
+}
+
+func TestAnnotationData_ValidateRange(t *testing.T) {
+	rangeTests := []struct {
+		name    string
+		Range   []int
+		wantErr error
+		valid   bool
+	}{
+		{"valid", []int{65, 70}, nil, true},
+		{"point", []int{65, 65}, nil, true},
+		{"empty", nil, ErrEmptyRange, false},
+		{"single", []int{65}, ErrNoRangeEnd, false},
+		{"negative", []int{-1, 5}, nil, false},
+		{"backwards", []int{70, 65}, nil, false},
+		{"three values", []int{1, 2, 3}, nil, false},
+	}
+
+	for _, c := range rangeTests {
+		t.Run(c.name, func(t *testing.T) {
+			err := AnnotationData{Range: c.Range}.ValidateRange()
+
+			if (err == nil) != c.valid {
+				t.Fatalf("got %v, want valid %v", err, c.valid)
+			}
+
+			if c.wantErr != nil && !errors.Is(err, c.wantErr) {
+				t.Errorf("got %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}
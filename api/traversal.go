@@ -0,0 +1,149 @@
+package api
+
+import "iter"
+
+// Position locates a lesson in the course structure.
+type Position struct {
+	// Index of the section in CourseData.Sections, and the section itself.
+	SectionIndex int
+	Section      SectionData
+
+	// 0-based position of the lesson in course order, and in its section.
+	Global    int
+	InSection int
+
+	// Number of lessons in the course, and in the section.
+	Total        int
+	SectionTotal int
+
+	// Previous and next lessons in course order, nil at the ends.
+	Prev *LessonData
+	Next *LessonData
+}
+
+// Returns true for the first lesson of its section.
+func (position Position) FirstInSection() bool {
+	return position.InSection == 0
+}
+
+// Returns true for the last lesson of its section.
+func (position Position) LastInSection() bool {
+	return position.InSection == position.SectionTotal-1
+}
+
+type positionedLesson struct {
+	position Position
+	lesson   LessonData
+}
+
+// Returns every lesson of the course in order, with its position.
+// References to lessons that do not exist are skipped, see Validate.
+func (course CourseData) ordered() []positionedLesson {
+	var ordered []positionedLesson
+	sectionTotals := make([]int, len(course.Sections))
+
+	for x, section := range course.Sections {
+		for _, lessonIndex := range section.LessonsIndex {
+			lesson, ok := course.LessonByIndex(lessonIndex)
+			if !ok {
+				continue
+			}
+
+			ordered = append(ordered, positionedLesson{
+				position: Position{
+					SectionIndex: x,
+					Section:      section,
+					Global:       len(ordered),
+					InSection:    sectionTotals[x],
+				},
+				lesson: lesson,
+			})
+			sectionTotals[x]++
+		}
+	}
+
+	for x := range ordered {
+		position := &ordered[x].position
+		position.Total = len(ordered)
+		position.SectionTotal = sectionTotals[position.SectionIndex]
+
+		if x > 0 {
+			position.Prev = &ordered[x-1].lesson
+		}
+		if x < len(ordered)-1 {
+			position.Next = &ordered[x+1].lesson
+		}
+	}
+
+	return ordered
+}
+
+// Iterates over every lesson of the course in order.
+func (course CourseData) All() iter.Seq2[Position, LessonData] {
+	return func(yield func(Position, LessonData) bool) {
+		for _, positioned := range course.ordered() {
+			if !yield(positioned.position, positioned.lesson) {
+				return
+			}
+		}
+	}
+}
+
+// Returns the number of lessons All iterates over.
+func (course CourseData) LessonCount() int {
+	return len(course.ordered())
+}
+
+// Iterates over the lessons of the section at index i of course.Sections.
+// Positions are the same as the ones of All.
+func (course CourseData) Section(i int) iter.Seq2[Position, LessonData] {
+	return func(yield func(Position, LessonData) bool) {
+		for position, lesson := range course.All() {
+			if position.SectionIndex != i {
+				continue
+			}
+
+			if !yield(position, lesson) {
+				return
+			}
+		}
+	}
+}
+
+// Returns the position of the lesson with the given index.
+func (course CourseData) PositionOf(index int) (Position, bool) {
+	for position, lesson := range course.All() {
+		if lesson.Index == index {
+			return position, true
+		}
+	}
+
+	return Position{}, false
+}
+
+// Returns the lesson with the given index.
+func (course CourseData) LessonByIndex(index int) (LessonData, bool) {
+	if lessonHash, ok := course.LessonsHash[index]; ok {
+		lesson, ok := course.Lessons[lessonHash]
+		return lesson, ok
+	}
+
+	for _, lesson := range course.Lessons {
+		if lesson.Index == index {
+			return lesson, true
+		}
+	}
+
+	return LessonData{}, false
+}
+
+// Returns the lesson with the given slug.
+func (course CourseData) LessonBySlug(slug string) (LessonData, bool) {
+	for _, lesson := range course.Lessons {
+		if lesson.Slug == slug {
+			return lesson, true
+		}
+	}
+
+	return LessonData{}, false
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func traversalCourse() CourseData {
+	course := CourseData{
+		Slug: "go-basics",
+		Sections: Sections{
+			{Title: "Intro", LessonsIndex: []int{0, 1}},
+			{Title: "Empty", LessonsIndex: []int{}},
+			{Title: "Outro", LessonsIndex: []int{2}},
+		},
+		Lessons: lessons{
+			"a": {Slug: "welcome", Index: 0},
+			"b": {Slug: "setup", Index: 1},
+			"c": {Slug: "bye", Index: 2},
+		},
+	}
+	course.populateLessonsHash()
+
+	return course
+}
+
+func TestCourseData_All(t *testing.T) {
+	type step struct {
+		slug                       string
+		section, global, inSection int
+		total, sectionTotal        int
+		prev, next                 string
+	}
+
+	var answer []step
+	for position, lesson := range traversalCourse().All() {
+		current := step{
+			slug:         lesson.Slug,
+			section:      position.SectionIndex,
+			global:       position.Global,
+			inSection:    position.InSection,
+			total:        position.Total,
+			sectionTotal: position.SectionTotal,
+		}
+		if position.Prev != nil {
+			current.prev = position.Prev.Slug
+		}
+		if position.Next != nil {
+			current.next = position.Next.Slug
+		}
+
+		answer = append(answer, current)
+	}
+
+	want := []step{
+		{"welcome", 0, 0, 0, 3, 2, "", "setup"},
+		{"setup", 0, 1, 1, 3, 2, "welcome", "bye"},
+		{"bye", 2, 2, 0, 3, 1, "setup", ""},
+	}
+	if !reflect.DeepEqual(answer, want) {
+		t.Errorf("got %+v, want %+v", answer, want)
+	}
+}
+
+func TestCourseData_LessonCount(t *testing.T) {
+	course := traversalCourse()
+	// Lessons that no section references are not part of the course.
+	course.Lessons["d"] = LessonData{Slug: "unused", Index: 3}
+	course.populateLessonsHash()
+
+	if got := course.LessonCount(); got != 3 {
+		t.Errorf("got %d, want %d", got, 3)
+	}
+}
+
+func TestCourseData_Section(t *testing.T) {
+	course := traversalCourse()
+
+	sectionTests := []struct {
+		section int
+		want    []string
+	}{
+		{0, []string{"welcome", "setup"}},
+		{1, nil},
+		{2, []string{"bye"}},
+		{3, nil},
+	}
+
+	for _, c := range sectionTests {
+		var answer []string
+		for _, lesson := range course.Section(c.section) {
+			answer = append(answer, lesson.Slug)
+		}
+
+		if !reflect.DeepEqual(answer, c.want) {
+			t.Errorf("section %d: got %v, want %v", c.section, answer, c.want)
+		}
+	}
+}
+
+func TestCourseData_LessonLookups(t *testing.T) {
+	course := traversalCourse()
+
+	if lesson, ok := course.LessonByIndex(1); !ok || lesson.Slug != "setup" {
+		t.Errorf("LessonByIndex(1): got %v %v, want setup", lesson.Slug, ok)
+	}
+
+	if _, ok := course.LessonByIndex(7); ok {
+		t.Error("LessonByIndex(7): got a lesson, want none")
+	}
+
+	if lesson, ok := course.LessonBySlug("bye"); !ok || lesson.Index != 2 {
+		t.Errorf("LessonBySlug(bye): got %v %v, want index 2", lesson.Index, ok)
+	}
+
+	if position, ok := course.PositionOf(2); !ok || position.SectionIndex != 2 || position.Prev.Slug != "setup" {
+		t.Errorf("PositionOf(2): got %+v %v", position, ok)
+	}
+}
+
+func TestCourseData_HugeLessonIndex(t *testing.T) {
+	course := traversalCourse()
+	course.Lessons["d"] = LessonData{Slug: "huge", Index: 1 << 40}
+	course.populateLessonsHash()
+
+	if lesson, ok := course.LessonByIndex(1 << 40); !ok || lesson.Slug != "huge" {
+		t.Errorf("got %v %v, want the lesson huge", lesson, ok)
+	}
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Severity int
+
+const (
+	// The course can still be generated, but the result may be incomplete.
+	SeverityWarning Severity = iota
+	// The course can not be generated correctly.
+	SeverityFatal
+)
+
+func (severity Severity) String() string {
+	if severity == SeverityFatal {
+		return "fatal"
+	}
+
+	return "warning"
+}
+
+type ProblemKind string
+
+const (
+	ProblemMissingLesson      ProblemKind = "missing lesson"
+	ProblemDuplicateIndex     ProblemKind = "duplicate index"
+	ProblemInvalidIndex       ProblemKind = "invalid index"
+	ProblemUnreferencedLesson ProblemKind = "unreferenced lesson"
+	ProblemEmptySection       ProblemKind = "empty section"
+	ProblemInvalidDuration    ProblemKind = "invalid duration"
+	ProblemInvalidTimestamp   ProblemKind = "invalid timestamp"
+	ProblemInvalidDate        ProblemKind = "invalid date"
+	ProblemInvalidRange       ProblemKind = "invalid annotation range"
+)
+
+type Problem struct {
+	Severity Severity
+	Kind     ProblemKind
+	Message  string
+}
+
+func (problem Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s", problem.Severity, problem.Kind, problem.Message)
+}
+
+// ValidationReport lists the consistency problems of a course.
+type ValidationReport struct {
+	Course   string
+	Problems []Problem
+}
+
+// Returns true if any of the problems is fatal.
+func (report ValidationReport) Fatal() bool {
+	return slices.ContainsFunc(report.Problems, func(problem Problem) bool {
+		return problem.Severity == SeverityFatal
+	})
+}
+
+// Returns the problems with the given severity.
+func (report ValidationReport) Filter(severity Severity) []Problem {
+	var problems []Problem
+	for _, problem := range report.Problems {
+		if problem.Severity == severity {
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems
+}
+
+func (report *ValidationReport) add(severity Severity, kind ProblemKind, format string, args ...any) {
+	report.Problems = append(report.Problems, Problem{
+		Severity: severity,
+		Kind:     kind,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (report ValidationReport) String() string {
+	var result strings.Builder
+
+	if len(report.Problems) == 0 {
+		result.WriteString(fmt.Sprintf("%s: no problems found.\n", report.Course))
+		return result.String()
+	}
+
+	result.WriteString(fmt.Sprintf(
+		"%s: %d problem(s), %d fatal.\n",
+		report.Course, len(report.Problems), len(report.Filter(SeverityFatal)),
+	))
+	for _, problem := range report.Problems {
+		result.WriteString(fmt.Sprintf("\t%s\n", problem))
+	}
+
+	return result.String()
+}
+
+// Checks that the sections and the lessons of the course are consistent
+// with each other.
+func (course CourseData) Validate() ValidationReport {
+	report := ValidationReport{Course: course.Slug}
+
+	if course.DatePublished != "" {
+		if _, err := ParseDate(course.DatePublished); err != nil {
+			report.add(SeverityWarning, ProblemInvalidDate, "publish date: %v", err)
+		}
+	}
+
+	lessonsByIndex := make(map[int][]string)
+	for lessonHash, lesson := range course.Lessons {
+		lessonsByIndex[lesson.Index] = append(lessonsByIndex[lesson.Index], lessonHash)
+	}
+
+	for _, index := range sortedKeys(lessonsByIndex) {
+		hashes := lessonsByIndex[index]
+		slices.Sort(hashes)
+
+		if index < 0 {
+			report.add(SeverityFatal, ProblemInvalidIndex, "lesson(s) %s have the negative index %d", strings.Join(hashes, ", "), index)
+		}
+
+		if len(hashes) > 1 {
+			report.add(SeverityFatal, ProblemDuplicateIndex, "lessons %s share the index %d", strings.Join(hashes, ", "), index)
+		}
+
+		for _, lessonHash := range hashes {
+			lesson := course.Lessons[lessonHash]
+			if lesson.Timestamp == "" {
+				continue
+			}
+
+			if _, _, err := ParseTimestamp(lesson.Timestamp); err != nil {
+				report.add(SeverityWarning, ProblemInvalidTimestamp, "lesson %d (%s): %v", index, lesson.Slug, err)
+			}
+		}
+
+		for _, lessonHash := range hashes {
+			lesson := course.Lessons[lessonHash]
+			for x, annotation := range lesson.Annotations {
+				if err := annotation.ValidateRange(); err != nil {
+					report.add(SeverityWarning, ProblemInvalidRange, "lesson %d (%s): annotation %d: %v", index, lesson.Slug, x, err)
+				}
+			}
+		}
+	}
+
+	referencedBy := make(map[int]int)
+	for x, section := range course.Sections {
+		if len(section.LessonsIndex) == 0 {
+			report.add(SeverityWarning, ProblemEmptySection, "section %d (%s) has no lessons", x, section.Title)
+		}
+
+		if section.RawDuration != "" {
+			if _, err := ParseDuration(section.RawDuration); err != nil {
+				report.add(SeverityWarning, ProblemInvalidDuration, "section %d (%s): %v", x, section.Title, err)
+			}
+		}
+
+		for _, lessonIndex := range section.LessonsIndex {
+			if previous, ok := referencedBy[lessonIndex]; ok {
+				report.add(SeverityFatal, ProblemDuplicateIndex, "lesson %d is referenced by section %d and section %d", lessonIndex, previous, x)
+				continue
+			}
+			referencedBy[lessonIndex] = x
+
+			if _, ok := lessonsByIndex[lessonIndex]; !ok {
+				report.add(SeverityFatal, ProblemMissingLesson, "section %d (%s) references lesson %d, which does not exist", x, section.Title, lessonIndex)
+			}
+		}
+	}
+
+	for _, index := range sortedKeys(lessonsByIndex) {
+		if _, ok := referencedBy[index]; ok || index < 0 {
+			continue
+		}
+
+		for _, lessonHash := range lessonsByIndex[index] {
+			lesson := course.Lessons[lessonHash]
+			report.add(SeverityWarning, ProblemUnreferencedLesson, "lesson %d (%s) is not part of any section and will not be generated", index, lesson.Slug)
+		}
+	}
+
+	return report
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCourseData_Validate(t *testing.T) {
+	validateTests := []struct {
+		name      string
+		sections  Sections
+		lessons   lessons
+		wantKinds []ProblemKind
+		wantFatal bool
+	}{
+		{
+			name:     "consistent",
+			sections: Sections{{Title: "Intro", RawDuration: "5m 10s", LessonsIndex: []int{0, 1}}},
+			lessons:  lessons{"a": {Index: 0}, "b": {Index: 1}},
+		},
+		{
+			name:      "missing lesson",
+			sections:  Sections{{Title: "Intro", LessonsIndex: []int{0, 2}}},
+			lessons:   lessons{"a": {Index: 0}},
+			wantKinds: []ProblemKind{ProblemMissingLesson},
+			wantFatal: true,
+		},
+		{
+			name:      "duplicate lesson index",
+			sections:  Sections{{Title: "Intro", LessonsIndex: []int{0}}},
+			lessons:   lessons{"a": {Index: 0}, "b": {Index: 0}},
+			wantKinds: []ProblemKind{ProblemDuplicateIndex},
+			wantFatal: true,
+		},
+		{
+			name:      "lesson in two sections",
+			sections:  Sections{{Title: "Intro", LessonsIndex: []int{0}}, {Title: "Outro", LessonsIndex: []int{0}}},
+			lessons:   lessons{"a": {Index: 0}},
+			wantKinds: []ProblemKind{ProblemDuplicateIndex},
+			wantFatal: true,
+		},
+		{
+			name:      "unreferenced lesson and empty final section",
+			sections:  Sections{{Title: "Intro", LessonsIndex: []int{0}}, {Title: "Outro", LessonsIndex: []int{}}},
+			lessons:   lessons{"a": {Index: 0}, "b": {Index: 1}},
+			wantKinds: []ProblemKind{ProblemEmptySection, ProblemUnreferencedLesson},
+		},
+		{
+			name:      "invalid duration",
+			sections:  Sections{{Title: "Intro", RawDuration: "forever", LessonsIndex: []int{0}}},
+			lessons:   lessons{"a": {Index: 0}},
+			wantKinds: []ProblemKind{ProblemInvalidDuration},
+		},
+		{
+			name:     "malformed annotation ranges",
+			sections: Sections{{Title: "Intro", LessonsIndex: []int{0}}},
+			lessons: lessons{"a": {Index: 0, Annotations: Annotations{
+				{Range: []int{65, 70}},
+				{Range: []int{}},
+				{Range: []int{-5, 10}},
+			}}},
+			wantKinds: []ProblemKind{ProblemInvalidRange, ProblemInvalidRange},
+		},
+	}
+
+	for _, c := range validateTests {
+		t.Run(c.name, func(t *testing.T) {
+			course := CourseData{Slug: "course", Sections: c.sections, Lessons: c.lessons}
+			course.populateLessonsHash()
+
+			report := course.Validate()
+
+			var kinds []ProblemKind
+			for _, problem := range report.Problems {
+				kinds = append(kinds, problem.Kind)
+			}
+
+			if !reflect.DeepEqual(kinds, c.wantKinds) {
+				t.Errorf("got %v, want %v\n%s", kinds, c.wantKinds, report)
+			}
+
+			if report.Fatal() != c.wantFatal {
+				t.Errorf("got fatal %v, want %v", report.Fatal(), c.wantFatal)
+			}
+		})
+	}
+}
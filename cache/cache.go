@@ -2,26 +2,74 @@ package cache
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// Extension of the cached course payloads. List and Remove only touch
+// these files, so that a cache dir set to a folder holding other files
+// keeps them.
+const payloadExtension = ".json"
+
 var cache CacheDir
 var cacheError error
+var cacheOptions Options
+
+// ErrDisabled is returned by NewCache when the cache is disabled.
+var ErrDisabled = errors.New("cache is disabled")
+
+// Options configures the cache returned by NewCache.
+type Options struct {
+	// Folder of the cache, <user cache dir>/fem-helper if empty.
+	Dir string
+	// Fetch courses every time instead of caching them.
+	Disabled bool
+}
+
+// Configures the cache returned by the next calls of NewCache.
+func Configure(options Options) {
+	cacheOptions = options
+	cache, cacheError = "", nil
+}
+
+// Returns the folder of the cache, without creating it.
+func Dir() (string, error) {
+	if cacheOptions.Dir != "" {
+		return cacheOptions.Dir, nil
+	}
+
+	return DefaultDir()
+}
+
+// Returns the folder of the cache unless Options.Dir is set.
+func DefaultDir() (string, error) {
+	cdir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cdir, "fem-helper"), nil
+}
 
 type CacheDir string
 
 func NewCache() (CacheDir, error) {
+	if cacheOptions.Disabled {
+		return "", ErrDisabled
+	}
+
 	if cache != "" {
 		return cache, cacheError
 	}
 
 	var cdir string
-	cdir, cacheError = os.UserCacheDir()
+	cdir, cacheError = Dir()
 	if cacheError != nil {
 		return "", cacheError
 	}
-	cache = CacheDir(filepath.Join(cdir, "fem-helper"))
+	cache = CacheDir(cdir)
 
 	_, err := os.Stat(string(cache))
 	if errors.Is(err, os.ErrNotExist) {
@@ -56,3 +104,51 @@ func (cache *CacheDir) GetAbsolutePath(relativePath string) string {
 func (cache CacheDir) String() string {
 	return string(cache)
 }
+
+// Returns the names of the course payloads in cache, sorted. A cache that
+// does not exist yet is empty.
+func (cache *CacheDir) List() ([]string, error) {
+	entries, err := os.ReadDir(string(*cache))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var filenames []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && filepath.Ext(entry.Name()) == payloadExtension {
+			filenames = append(filenames, entry.Name())
+		}
+	}
+
+	return filenames, nil
+}
+
+// Removes the course payload filename from cache. Removing a missing file
+// is not an error.
+func (cache *CacheDir) Remove(filename string) error {
+	if filepath.Ext(filename) != payloadExtension {
+		return fmt.Errorf("%q is not a cached course payload", filename)
+	}
+	if err := ValidateSlug(strings.TrimSuffix(filename, payloadExtension)); err != nil {
+		return err
+	}
+
+	err := os.Remove(cache.GetAbsolutePath(filename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Checks that slug names a file directly in the cache.
+func ValidateSlug(slug string) error {
+	if slug == "" || strings.ContainsAny(slug, `/\`) || strings.Contains(slug, "..") {
+		return fmt.Errorf("invalid course slug %q", slug)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCacheDir_ListRemove(t *testing.T) {
+	root := t.TempDir()
+	cache := CacheDir(filepath.Join(root, "cache"))
+	if err := os.Mkdir(cache.String(), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"go-basics.json", "notes.md", "../outside.json"} {
+		if err := os.WriteFile(cache.GetAbsolutePath(name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filenames, err := cache.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"go-basics.json"}; !reflect.DeepEqual(filenames, want) {
+		t.Errorf("got %v, want %v", filenames, want)
+	}
+
+	removeTests := []struct {
+		filename string
+		ok       bool
+	}{
+		{"go-basics.json", true},
+		{"missing.json", true},
+		{"notes.md", false},
+		{"../outside.json", false},
+		{"a/../../outside.json", false},
+	}
+
+	for _, c := range removeTests {
+		t.Run(c.filename, func(t *testing.T) {
+			if err := cache.Remove(c.filename); (err == nil) != c.ok {
+				t.Errorf("got %v, want ok %v", err, c.ok)
+			}
+		})
+	}
+
+	for _, name := range []string{"notes.md", "../outside.json"} {
+		if _, err := os.Stat(cache.GetAbsolutePath(name)); err != nil {
+			t.Errorf("%s was removed: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/cache"
+	"github.com/raphaeltannous/fem-helper/templater"
+)
+
+// command is a subcommand of fem-helper, selected by the first argument.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// Subcommands in the order usage lists them. Without one, the course is
+// generated.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"generate", "Generate the notes of a course. (default)", generateCommand},
+		{"info", "Print a summary of a course.", infoCommand},
+		{"list", "List the sections and lessons of a course.", listCommand},
+		{"cache", "Show, list or clean the cached course payloads.", cacheCommand},
+		{"validate", "Check a course for consistency problems.", validateCommand},
+		{"schema-check", "Compare a course payload against the expected model.", schemaCheckCommand},
+		{"export", "Write the course data as JSON.", exportCommand},
+		{"config", "Show the effective configuration.", configCommand},
+		{"templates", "List, print or copy out the default templates.", templatesCommand},
+		{"version", "Print the version of fem-helper.", versionCommand},
+		{"help", "Print the help of a command.", helpCommand},
+	}
+}
+
+// Returns the command called name.
+func lookupCommand(name string) (command, bool) {
+	for _, command := range commands {
+		if command.name == name {
+			return command, true
+		}
+	}
+
+	return command{}, false
+}
+
+// Prints the commands of fem-helper.
+func usage() {
+	output := flag.CommandLine.Output()
+
+	fmt.Fprintln(output, "Usage: fem-helper <command> [flags] [arguments]")
+	fmt.Fprintln(output, "       fem-helper -c <course-slug> -o <output-dir> [flags]")
+	fmt.Fprintln(output, "\nCommands:")
+	for _, command := range commands {
+		fmt.Fprintf(output, "  %-14s %s\n", command.name, command.summary)
+	}
+	fmt.Fprintln(output, "\nRun \"fem-helper help <command>\" for the flags of a command.")
+}
+
+// Returns a flag set named after the command, whose usage prints synopsis
+// and summary before the flags.
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(name, flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage: fem-helper %s %s\n", name, synopsis)
+		if command, ok := lookupCommand(name); ok {
+			fmt.Fprintf(flagSet.Output(), "\n%s\n", command.summary)
+		}
+		flagSet.PrintDefaults()
+	}
+
+	return flagSet
+}
+
+// Parses args with flagSet, and exits with the usage of flagSet unless
+// exactly nargs arguments remain, or at least nargs with a negative nargs.
+func parseArgs(flagSet *flag.FlagSet, args []string, nargs int) {
+	flagSet.Parse(args)
+
+	if nargs >= 0 && flagSet.NArg() != nargs || nargs < 0 && flagSet.NArg() < -nargs {
+		flagSet.Usage()
+		os.Exit(2)
+	}
+}
+
+// Prints the help of a command, or the commands without one.
+func helpCommand(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return nil
+	}
+
+	if args[0] == "generate" {
+		generateFlags.Usage()
+		return nil
+	}
+
+	command, ok := lookupCommand(args[0])
+	if !ok || command.name == "help" {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	return command.run([]string{"-h"})
+}
+
+// Prints the summary of a course.
+func infoCommand(args []string) error {
+	flagSet := newFlagSet("info", "[-profile name] <course-slug>")
+	profile := profileFlag(flagSet)
+	parseArgs(flagSet, args, 1)
+
+	if _, err := loadConfig(*profile); err != nil {
+		return err
+	}
+
+	course, err := api.NewCourse(flagSet.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(course)
+	return nil
+}
+
+// Prints the sections of a course and their lessons in order.
+func listCommand(args []string) error {
+	flagSet := newFlagSet("list", "[-annotations] [-profile name] <course-slug>")
+	withAnnotations := flagSet.Bool("annotations", false, "Print the annotations of every lesson.")
+	profile := profileFlag(flagSet)
+	parseArgs(flagSet, args, 1)
+
+	if _, err := loadConfig(*profile); err != nil {
+		return err
+	}
+
+	course, err := api.NewCourse(flagSet.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	for position, lesson := range course.All() {
+		if position.FirstInSection() {
+			fmt.Printf("%d. %s%s\n", position.SectionIndex, position.Section.Title, listDuration(position.Section.Duration))
+		}
+		fmt.Printf("\t%d. %s%s\n", lesson.Index, lesson.Title, listDuration(lesson.Duration))
+
+		if *withAnnotations {
+			for _, anno := range lesson.Annotations {
+				fmt.Printf("\t\t%s %s\n", strings.Join(anno.GetReadableRange(), " -> "), strings.ReplaceAll(anno.Message, "\n", " "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns duration in parentheses, or nothing if it is unknown.
+func listDuration(duration time.Duration) string {
+	if duration <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%v)", duration)
+}
+
+// Manages the cached course payloads.
+func cacheCommand(args []string) error {
+	flagSet := newFlagSet("cache", "[-profile name] path | list | clean [course-slug...]")
+	profile := profileFlag(flagSet)
+	parseArgs(flagSet, args, -1)
+
+	if _, err := loadConfig(*profile); err != nil {
+		return err
+	}
+
+	dir, err := cache.Dir()
+	if err != nil {
+		return err
+	}
+	cacheDir := cache.CacheDir(dir)
+
+	action, slugs := flagSet.Arg(0), flagSet.Args()[1:]
+	if action != "clean" && len(slugs) > 0 {
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	switch action {
+	case "path":
+		fmt.Println(cacheDir)
+	case "list":
+		filenames, err := cacheDir.List()
+		if err != nil {
+			return err
+		}
+
+		for _, filename := range filenames {
+			fmt.Println(filename)
+		}
+	case "clean":
+		filenames := make([]string, len(slugs))
+		for x, slug := range slugs {
+			if err := cache.ValidateSlug(slug); err != nil {
+				return err
+			}
+			filenames[x] = slug + ".json"
+		}
+
+		if len(slugs) == 0 {
+			filenames, err = cacheDir.List()
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, filename := range filenames {
+			if err := cacheDir.Remove(filename); err != nil {
+				return err
+			}
+		}
+	default:
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	return nil
+}
+
+// Prints the consistency report of a course, and fails if it has fatal
+// problems.
+func validateCommand(args []string) error {
+	flagSet := newFlagSet("validate", "[-profile name] <course-slug>")
+	profile := profileFlag(flagSet)
+	parseArgs(flagSet, args, 1)
+
+	if _, err := loadConfig(*profile); err != nil {
+		return err
+	}
+
+	course, err := api.NewCourse(flagSet.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report := course.Validate()
+	fmt.Print(report)
+
+	if report.Fatal() {
+		return errors.New("course has fatal problems")
+	}
+
+	return nil
+}
+
+// Compares the payload of a course, or a payload file, against the
+// expected model, and fails if it drifted.
+func schemaCheckCommand(args []string) error {
+	flagSet := newFlagSet("schema-check", "[-file payload.json] [-profile name] [course-slug]")
+	payloadFile := flagSet.String("file", "", "Check this payload file instead of fetching the course.")
+	profile := profileFlag(flagSet)
+	flagSet.Parse(args)
+
+	if _, err := loadConfig(*profile); err != nil {
+		return err
+	}
+
+	var (
+		report api.SchemaReport
+		err    error
+	)
+
+	switch {
+	case *payloadFile != "" && flagSet.NArg() == 0:
+		var payload []byte
+		payload, err = os.ReadFile(*payloadFile)
+		if err != nil {
+			return err
+		}
+		report, err = api.CheckSchema(payload)
+	case *payloadFile == "" && flagSet.NArg() == 1:
+		report, err = api.CheckCourseSchema(flagSet.Arg(0))
+	default:
+		flagSet.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(report)
+
+	if report.Drift() {
+		return errors.New("payload drifted from the expected model")
+	}
+
+	return nil
+}
+
+// courseExport is the JSON written by export: the course with its sections
+// and their lessons in order. Durations are in seconds.
+type courseExport struct {
+	Slug        string          `json:"slug"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Published   string          `json:"published,omitempty"`
+	Duration    int             `json:"duration"`
+	Sections    []sectionExport `json:"sections"`
+}
+
+type sectionExport struct {
+	Title    string         `json:"title"`
+	Duration int            `json:"duration"`
+	Lessons  []lessonExport `json:"lessons"`
+}
+
+type lessonExport struct {
+	Index       int             `json:"index"`
+	Slug        string          `json:"slug"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Start       int             `json:"start"`
+	Duration    int             `json:"duration"`
+	Annotations api.Annotations `json:"annotations,omitempty"`
+}
+
+// Returns the export of course.
+func newCourseExport(course api.CourseData) courseExport {
+	export := courseExport{
+		Slug:        course.Slug,
+		Title:       course.Title,
+		Description: course.Description,
+		Duration:    int(course.Duration().Seconds()),
+		Sections:    make([]sectionExport, len(course.Sections)),
+	}
+	if !course.Published.IsZero() {
+		export.Published = course.Published.Format(time.DateOnly)
+	}
+
+	for x, section := range course.Sections {
+		export.Sections[x] = sectionExport{
+			Title:    section.Title,
+			Duration: int(section.Duration.Seconds()),
+			Lessons:  []lessonExport{},
+		}
+	}
+
+	for position, lesson := range course.All() {
+		section := &export.Sections[position.SectionIndex]
+		section.Lessons = append(section.Lessons, lessonExport{
+			Index:       lesson.Index,
+			Slug:        lesson.Slug,
+			Title:       lesson.Title,
+			Description: lesson.Description,
+			Start:       int(lesson.Start.Seconds()),
+			Duration:    int(lesson.Duration.Seconds()),
+			Annotations: lesson.Annotations,
+		})
+	}
+
+	return export
+}
+
+// Writes the course data as JSON, to stdout or a file.
+func exportCommand(args []string) error {
+	flagSet := newFlagSet("export", "[-o file.json] [-profile name] <course-slug>")
+	outputFile := flagSet.String("o", "", "Write the JSON to this file instead of stdout.")
+	profile := profileFlag(flagSet)
+	parseArgs(flagSet, args, 1)
+
+	if _, err := loadConfig(*profile); err != nil {
+		return err
+	}
+
+	course, err := api.NewCourse(flagSet.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(newCourseExport(course), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if *outputFile == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(*outputFile, data, 0644)
+}
+
+// Lists the default templates, prints one, or writes them to a folder to
+// start custom templates from.
+func templatesCommand(args []string) error {
+	flagSet := newFlagSet("templates", "list | show <name> | dump <dir>")
+	parseArgs(flagSet, args, -1)
+
+	action := flagSet.Arg(0)
+	if action != "list" && flagSet.NArg() != 2 || action == "list" && flagSet.NArg() != 1 {
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	switch action {
+	case "list":
+		for _, name := range templater.TemplateNames {
+			fmt.Println(name)
+		}
+	case "show":
+		text, err := templater.DefaultTemplate(flagSet.Arg(1))
+		if err != nil {
+			return fmt.Errorf("unknown template %q, expected one of: %s", flagSet.Arg(1), strings.Join(templater.TemplateNames, ", "))
+		}
+
+		_, err = os.Stdout.Write(text)
+		return err
+	case "dump":
+		dir := flagSet.Arg(1)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		for _, name := range templater.TemplateNames {
+			text, err := templater.DefaultTemplate(name)
+			if err != nil {
+				return err
+			}
+
+			// Never overwrite templates that may have been customized.
+			file, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil {
+				return err
+			}
+			if _, err := file.Write(text); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		}
+	default:
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	return nil
+}
+
+// Version of fem-helper, set at build time with
+// -ldflags "-X main.version=v1.2.3". The module version is used otherwise.
+var version string
+
+// Prints the version of fem-helper and of the Go toolchain it was built
+// with.
+func versionCommand(args []string) error {
+	flagSet := newFlagSet("version", "")
+	parseArgs(flagSet, args, 0)
+
+	current, goVersion := version, runtime.Version()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if current == "" {
+			current = info.Main.Version
+		}
+		goVersion = info.GoVersion
+	}
+	if current == "" {
+		current = "(devel)"
+	}
+
+	fmt.Printf("fem-helper %s %s\n", current, goVersion)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLookupCommand(t *testing.T) {
+	lookupTests := []struct {
+		name string
+		ok   bool
+	}{
+		{"generate", true},
+		{"config", true},
+		{"help", true},
+		{"Generate", false},
+		{"", false},
+	}
+
+	for _, c := range lookupTests {
+		t.Run(c.name, func(t *testing.T) {
+			command, ok := lookupCommand(c.name)
+			if ok != c.ok {
+				t.Fatalf("got %v, want %v", ok, c.ok)
+			}
+
+			if ok && (command.name != c.name || command.run == nil) {
+				t.Errorf("got command %q, want %q", command.name, c.name)
+			}
+		})
+	}
+}
+
+func TestNewFlagSet_Usage(t *testing.T) {
+	flagSet := newFlagSet("list", "[-annotations] <course-slug>")
+	flagSet.Bool("annotations", false, "Print the annotations of every lesson.")
+
+	var output bytes.Buffer
+	flagSet.SetOutput(&output)
+	flagSet.Usage()
+
+	for _, want := range []string{
+		"Usage: fem-helper list [-annotations] <course-slug>\n",
+		"\nList the sections and lessons of a course.\n",
+		"-annotations",
+	} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("usage does not contain %q:\n%s", want, output.String())
+		}
+	}
+}
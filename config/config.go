@@ -0,0 +1,408 @@
+// Package config reads the settings of fem-helper from config files,
+// profiles and environment variables, and remembers where each of them
+// came from.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Name of the config file in the config folder of the user.
+	FileName = "config.toml"
+	// Name of the config file of a vault, looked up from the current
+	// folder up.
+	VaultFileName = ".fem-helper.toml"
+	// Prefix of the environment variables overriding the config.
+	EnvPrefix = "FEM_HELPER_"
+)
+
+// Kind is the type of the value of a key.
+type Kind int
+
+const (
+	String Kind = iota
+	Bool
+	Int
+	List
+)
+
+func (kind Kind) String() string {
+	return [...]string{"a string", "a boolean", "an integer", "an array of strings"}[kind]
+}
+
+// Key is a setting of the config file.
+type Key struct {
+	// Dotted name of the key, such as naming.pad.
+	Name string
+	Kind Kind
+	// Paths are resolved against the folder of the config file they are
+	// set in, and ~ against the home folder.
+	Path bool
+}
+
+// Keys of the config, in the order they are shown.
+var Keys = []Key{
+	{Name: "output_dir", Kind: String, Path: true},
+	{Name: "tags", Kind: List},
+	{Name: "tag_prefix", Kind: String},
+	{Name: "flavor", Kind: String},
+	{Name: "templates", Kind: List, Path: true},
+	{Name: "naming.section_name", Kind: String},
+	{Name: "naming.lesson_name", Kind: String},
+	{Name: "naming.one_based", Kind: Bool},
+	{Name: "naming.pad", Kind: Int},
+	{Name: "naming.flat", Kind: Bool},
+	{Name: "naming.title_names", Kind: Bool},
+	{Name: "cache.dir", Kind: String, Path: true},
+	{Name: "cache.enabled", Kind: Bool},
+}
+
+// Name of the key selecting the profile used when none is given.
+const profileKey = "profile"
+
+// Name of the table holding the profiles.
+const profilesTable = "profiles"
+
+// Returns the key called name.
+func LookupKey(name string) (Key, bool) {
+	x := slices.IndexFunc(Keys, func(key Key) bool { return key.Name == name })
+	if x < 0 {
+		return Key{}, false
+	}
+
+	return Keys[x], true
+}
+
+// Returns the environment variable overriding key, such as
+// FEM_HELPER_NAMING_PAD.
+func (key Key) Env() string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key.Name))
+}
+
+// Value is the value of a key, and where it was set.
+type Value struct {
+	// string, bool, int64 or []string, following the Kind of the key.
+	Value any
+	// Where the value was set, such as a file path or an environment
+	// variable.
+	Source string
+}
+
+// Returns the value as a command line flag value. Lists are joined with
+// commas.
+func (value Value) Text() string {
+	switch v := value.Value.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Returns the value written in TOML.
+func (value Value) TOML() string {
+	switch v := value.Value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for x, item := range v {
+			quoted[x] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Config is the merged configuration.
+type Config struct {
+	// Values of the keys that are set, by key name.
+	Values map[string]Value
+	// Config files that were read, in the order they were applied.
+	Files []string
+	// Selected profile, empty if none, and where it was selected.
+	Profile       string
+	ProfileSource string
+}
+
+// Options configures Load.
+type Options struct {
+	// Config files in increasing priority. Files that do not exist are
+	// skipped.
+	Files []string
+	// Profile to apply, over the one selected by FEM_HELPER_PROFILE or the
+	// profile key of the files.
+	Profile string
+	// Environment variables as key=value, such as os.Environ().
+	Environ []string
+}
+
+// Returns the config files of fem-helper in increasing priority: the
+// config of the user, in $XDG_CONFIG_HOME/fem-helper or the config folder
+// of the OS, and the nearest .fem-helper.toml from the current folder up.
+func DefaultFiles() []string {
+	var files []string
+
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir, _ = os.UserConfigDir()
+	}
+	if configDir != "" {
+		files = append(files, filepath.Join(configDir, "fem-helper", FileName))
+	}
+
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			vaultFile := filepath.Join(dir, VaultFileName)
+			if _, err := os.Stat(vaultFile); err == nil {
+				files = append(files, vaultFile)
+				break
+			}
+
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	return files
+}
+
+// Returns the config merged from, in increasing priority: the files, the
+// selected profile of the files, and the environment.
+func Load(options Options) (Config, error) {
+	config := Config{Values: make(map[string]Value)}
+
+	// Profile selected by the files, the last one wins.
+	var fileProfile Value
+	profileValues := make(map[string]map[string]Value)
+
+	for _, file := range options.Files {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Config{}, err
+		}
+
+		entries, err := parseTOML(data)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if errors.As(err, &syntaxErr) {
+				syntaxErr.File = file
+			}
+			return Config{}, err
+		}
+		config.Files = append(config.Files, file)
+
+		for _, entry := range entries {
+			source := fmt.Sprintf("%s:%d", file, entry.line)
+
+			switch {
+			case len(entry.key) == 1 && entry.key[0] == profileKey:
+				name, ok := entry.value.(string)
+				if !ok || name == "" {
+					return Config{}, fmt.Errorf("%s: %s must be a profile name", source, profileKey)
+				}
+				fileProfile = Value{Value: name, Source: source}
+			case len(entry.key) > 2 && entry.key[0] == profilesTable:
+				name := entry.key[1]
+				value, err := fileValue(file, entry.key[2:], entry.value)
+				if err != nil {
+					return Config{}, fmt.Errorf("%s: %w", source, err)
+				}
+				value.Source = fmt.Sprintf("profile %s (%s)", name, source)
+
+				if profileValues[name] == nil {
+					profileValues[name] = make(map[string]Value)
+				}
+				profileValues[name][strings.Join(entry.key[2:], ".")] = value
+			default:
+				value, err := fileValue(file, entry.key, entry.value)
+				if err != nil {
+					return Config{}, fmt.Errorf("%s: %w", source, err)
+				}
+				value.Source = source
+				config.Values[strings.Join(entry.key, ".")] = value
+			}
+		}
+	}
+
+	env := environ(options.Environ)
+
+	switch {
+	case options.Profile != "":
+		config.Profile, config.ProfileSource = options.Profile, "--profile"
+	case env[EnvPrefix+"PROFILE"] != "":
+		config.Profile, config.ProfileSource = env[EnvPrefix+"PROFILE"], EnvPrefix+"PROFILE"
+	case fileProfile.Value != nil:
+		config.Profile, config.ProfileSource = fileProfile.Value.(string), fileProfile.Source
+	}
+
+	if config.Profile != "" {
+		values, ok := profileValues[config.Profile]
+		if !ok {
+			return Config{}, fmt.Errorf("unknown profile %q (selected by %s)", config.Profile, config.ProfileSource)
+		}
+
+		for name, value := range values {
+			config.Values[name] = value
+		}
+	}
+
+	for _, key := range Keys {
+		text, ok := env[key.Env()]
+		if !ok {
+			continue
+		}
+
+		value, err := parseEnv(key, text)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", key.Env(), err)
+		}
+		// Relative paths stay relative to the current folder, like the
+		// paths of flags.
+		if key.Path {
+			value.Value = resolvePaths(".", value.Value)
+		}
+		value.Source = key.Env()
+		config.Values[key.Name] = value
+	}
+
+	return config, nil
+}
+
+// Returns the value of key set to raw in file.
+func fileValue(file string, key []string, raw any) (Value, error) {
+	configKey, ok := LookupKey(strings.Join(key, "."))
+	if !ok {
+		return Value{}, fmt.Errorf("unknown key %s", joinKey(key))
+	}
+
+	var value any
+	switch configKey.Kind {
+	case String:
+		value, ok = raw.(string)
+	case Bool:
+		value, ok = raw.(bool)
+	case Int:
+		value, ok = raw.(int64)
+	case List:
+		var list []string
+		list, ok = stringList(raw)
+		value = list
+	}
+	if !ok {
+		return Value{}, fmt.Errorf("%s must be %s", configKey.Name, configKey.Kind)
+	}
+
+	if configKey.Path {
+		value = resolvePaths(filepath.Dir(file), value)
+	}
+
+	return Value{Value: value}, nil
+}
+
+// Returns raw as a list of strings. A single string is a list of one.
+func stringList(raw any) ([]string, bool) {
+	switch raw := raw.(type) {
+	case string:
+		return []string{raw}, true
+	case []any:
+		list := make([]string, len(raw))
+		for x, item := range raw {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list[x] = text
+		}
+		return list, true
+	default:
+		return nil, false
+	}
+}
+
+// Returns the path, or list of paths, value resolved against dir.
+func resolvePaths(dir string, value any) any {
+	switch value := value.(type) {
+	case string:
+		return resolvePath(dir, value)
+	case []string:
+		resolved := make([]string, len(value))
+		for x, path := range value {
+			resolved[x] = resolvePath(dir, path)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+func resolvePath(dir, path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// Returns the value of key set to text in the environment. Lists are
+// separated with commas.
+func parseEnv(key Key, text string) (Value, error) {
+	switch key.Kind {
+	case Bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return Value{}, fmt.Errorf("%q is not a boolean", text)
+		}
+		return Value{Value: value}, nil
+	case Int:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("%q is not an integer", text)
+		}
+		return Value{Value: value}, nil
+	case List:
+		var list []string
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return Value{Value: list}, nil
+	default:
+		return Value{Value: text}, nil
+	}
+}
+
+// Returns the environment variables of fem-helper by name.
+func environ(variables []string) map[string]string {
+	env := make(map[string]string)
+	for _, variable := range variables {
+		name, value, ok := strings.Cut(variable, "=")
+		if ok && strings.HasPrefix(name, EnvPrefix) {
+			env[name] = value
+		}
+	}
+
+	return env
+}
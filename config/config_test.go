@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Writes the config files and returns their paths.
+func writeFiles(t *testing.T, documents ...string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := make([]string, len(documents))
+	for x, document := range documents {
+		files[x] = filepath.Join(dir, strings.Repeat("v", x)+"config.toml")
+		if err := os.WriteFile(files[x], []byte(document), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return files
+}
+
+func TestLoad(t *testing.T) {
+	files := writeFiles(t, `
+output_dir = "notes"
+tags = ["fem"]
+flavor = "obsidian"
+profile = "work"
+
+[naming]
+pad = 2
+
+[profiles.work]
+tags = "work"
+
+[profiles.home.naming]
+flat = true
+`, `
+tag_prefix = "courses"
+flavor = "obsidian"
+`)
+	dir := filepath.Dir(files[0])
+
+	loadTests := []struct {
+		name    string
+		options Options
+		want    map[string]Value
+		profile string
+	}{
+		{
+			name:    "profile of the files",
+			options: Options{Files: files},
+			want: map[string]Value{
+				"output_dir": {Value: filepath.Join(dir, "notes"), Source: files[0] + ":2"},
+				"tags":       {Value: []string{"work"}, Source: "profile work (" + files[0] + ":11)"},
+				"flavor":     {Value: "obsidian", Source: files[1] + ":3"},
+				"tag_prefix": {Value: "courses", Source: files[1] + ":2"},
+				"naming.pad": {Value: int64(2), Source: files[0] + ":8"},
+			},
+			profile: "work",
+		},
+		{
+			name:    "selected profile and environment",
+			options: Options{Files: files, Profile: "home", Environ: []string{"FEM_HELPER_TAGS=a, b", "FEM_HELPER_NAMING_PAD=3", "HOME=/home/user"}},
+			want: map[string]Value{
+				"output_dir":  {Value: filepath.Join(dir, "notes"), Source: files[0] + ":2"},
+				"tags":        {Value: []string{"a", "b"}, Source: "FEM_HELPER_TAGS"},
+				"flavor":      {Value: "obsidian", Source: files[1] + ":3"},
+				"tag_prefix":  {Value: "courses", Source: files[1] + ":2"},
+				"naming.pad":  {Value: int64(3), Source: "FEM_HELPER_NAMING_PAD"},
+				"naming.flat": {Value: true, Source: "profile home (" + files[0] + ":14)"},
+			},
+			profile: "home",
+		},
+	}
+
+	for _, c := range loadTests {
+		t.Run(c.name, func(t *testing.T) {
+			config, err := Load(c.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(config.Values, c.want) {
+				t.Errorf("got %v, want %v", config.Values, c.want)
+			}
+			if config.Profile != c.profile {
+				t.Errorf("got profile %s, want %s", config.Profile, c.profile)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	errorTests := []struct {
+		name     string
+		document string
+		options  Options
+		want     string
+	}{
+		{"unknown key", "colour = 1\n", Options{}, "unknown key colour"},
+		{"wrong type", "[naming]\npad = \"2\"\n", Options{}, "naming.pad must be an integer"},
+		{"unknown profile", "", Options{Profile: "work"}, `unknown profile "work"`},
+		{"invalid environment", "", Options{Environ: []string{"FEM_HELPER_NAMING_FLAT=maybe"}}, "FEM_HELPER_NAMING_FLAT"},
+		{"syntax", "output_dir = \n", Options{}, ":1: expected a value"},
+	}
+
+	for _, c := range errorTests {
+		t.Run(c.name, func(t *testing.T) {
+			c.options.Files = writeFiles(t, c.document)
+
+			_, err := Load(c.options)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("got %v, want an error containing %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	config, err := Load(Options{Files: []string{filepath.Join(t.TempDir(), "config.toml")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.Values) != 0 || len(config.Files) != 0 {
+		t.Errorf("got %v from a missing file", config)
+	}
+}
+
+func TestLoadEnvPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config, err := Load(Options{Environ: []string{
+		"FEM_HELPER_OUTPUT_DIR=~/vault",
+		"FEM_HELPER_CACHE_DIR=cache",
+		"FEM_HELPER_TEMPLATES=~/templates/lesson.tmpl, /templates/course.tmpl",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"output_dir": filepath.Join(home, "vault"),
+		"cache.dir":  "cache",
+		"templates":  []string{filepath.Join(home, "templates/lesson.tmpl"), "/templates/course.tmpl"},
+	}
+	for name, value := range want {
+		if got := config.Values[name].Value; !reflect.DeepEqual(got, value) {
+			t.Errorf("%s: got %v, want %v", name, got, value)
+		}
+	}
+}
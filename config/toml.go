@@ -0,0 +1,410 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// entry is a key of a TOML document, with the table it is in prepended to
+// its parts.
+type entry struct {
+	key   []string
+	value any
+	line  int
+}
+
+// SyntaxError is a TOML document that can not be read.
+type SyntaxError struct {
+	// Path of the document, empty if unknown.
+	File string
+	Line int
+	Msg  string
+}
+
+func (err *SyntaxError) Error() string {
+	if err.File == "" {
+		return fmt.Sprintf("line %d: %s", err.Line, err.Msg)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", err.File, err.Line, err.Msg)
+}
+
+// Returns the keys of the TOML document data, in order.
+//
+// Only the subset of TOML config files need is read: tables, dotted and
+// quoted keys, basic and literal strings, integers, booleans and arrays of
+// them. Values are string, int64, bool or []any.
+func parseTOML(data []byte) ([]entry, error) {
+	if !utf8.Valid(data) {
+		return nil, &SyntaxError{Line: 1, Msg: "document is not valid UTF-8"}
+	}
+
+	parser := &tomlParser{input: string(data), line: 1}
+
+	var (
+		entries []entry
+		table   []string
+		seen    = make(map[string]bool)
+	)
+	for {
+		parser.skipBlank()
+		if parser.done() {
+			return entries, nil
+		}
+
+		line := parser.line
+		if parser.peek() == '[' {
+			header, err := parser.tableHeader()
+			if err != nil {
+				return nil, err
+			}
+			table = header
+
+			if seen[joinKey(table)] {
+				return nil, parser.errorf("table %s is defined twice", joinKey(table))
+			}
+			seen[joinKey(table)] = true
+		} else {
+			key, err := parser.key()
+			if err != nil {
+				return nil, err
+			}
+
+			parser.skipSpaces()
+			if parser.done() || parser.peek() != '=' {
+				return nil, parser.errorf("expected = after key %s", joinKey(key))
+			}
+			parser.pos++
+			parser.skipSpaces()
+
+			value, err := parser.value()
+			if err != nil {
+				return nil, err
+			}
+
+			key = append(append([]string(nil), table...), key...)
+			if seen[joinKey(key)] {
+				return nil, &SyntaxError{Line: line, Msg: fmt.Sprintf("key %s is defined twice", joinKey(key))}
+			}
+			seen[joinKey(key)] = true
+
+			entries = append(entries, entry{key: key, value: value, line: line})
+		}
+
+		if err := parser.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Returns key as written in TOML, with its parts quoted as needed.
+func joinKey(key []string) string {
+	parts := make([]string, len(key))
+	for x, part := range key {
+		parts[x] = part
+		if part == "" || strings.IndexFunc(part, func(r rune) bool { return !isBareKeyChar(r) }) >= 0 {
+			parts[x] = strconv.Quote(part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func isBareKeyChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-'
+}
+
+type tomlParser struct {
+	input string
+	pos   int
+	line  int
+}
+
+func (parser *tomlParser) done() bool {
+	return parser.pos >= len(parser.input)
+}
+
+func (parser *tomlParser) peek() byte {
+	return parser.input[parser.pos]
+}
+
+func (parser *tomlParser) errorf(format string, args ...any) error {
+	return &SyntaxError{Line: parser.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Skips spaces and tabs.
+func (parser *tomlParser) skipSpaces() {
+	for !parser.done() && (parser.peek() == ' ' || parser.peek() == '\t') {
+		parser.pos++
+	}
+}
+
+// Skips a comment up to the end of the line.
+func (parser *tomlParser) skipComment() {
+	if parser.done() || parser.peek() != '#' {
+		return
+	}
+
+	for !parser.done() && parser.peek() != '\n' {
+		parser.pos++
+	}
+}
+
+// Skips whitespace, comments and newlines.
+func (parser *tomlParser) skipBlank() {
+	for {
+		parser.skipSpaces()
+		parser.skipComment()
+
+		switch {
+		case parser.done():
+			return
+		case parser.peek() == '\n':
+			parser.line++
+		case strings.HasPrefix(parser.input[parser.pos:], "\r\n"):
+			parser.pos++
+			parser.line++
+		default:
+			return
+		}
+		parser.pos++
+	}
+}
+
+// Consumes the rest of the line, which may only hold a comment.
+func (parser *tomlParser) endOfLine() error {
+	parser.skipSpaces()
+	parser.skipComment()
+
+	switch {
+	case parser.done():
+		return nil
+	case parser.peek() == '\n':
+	case strings.HasPrefix(parser.input[parser.pos:], "\r\n"):
+		parser.pos++
+	default:
+		return parser.errorf("unexpected %q after value", parser.peek())
+	}
+
+	parser.pos++
+	parser.line++
+	return nil
+}
+
+// Reads a [table] header.
+func (parser *tomlParser) tableHeader() ([]string, error) {
+	parser.pos++
+	if !parser.done() && parser.peek() == '[' {
+		return nil, parser.errorf("arrays of tables are not supported")
+	}
+
+	parser.skipSpaces()
+	key, err := parser.key()
+	if err != nil {
+		return nil, err
+	}
+
+	parser.skipSpaces()
+	if parser.done() || parser.peek() != ']' {
+		return nil, parser.errorf("expected ] after table %s", joinKey(key))
+	}
+	parser.pos++
+
+	return key, nil
+}
+
+// Reads a bare, quoted or dotted key.
+func (parser *tomlParser) key() ([]string, error) {
+	var key []string
+	for {
+		part, err := parser.keyPart()
+		if err != nil {
+			return nil, err
+		}
+		key = append(key, part)
+
+		parser.skipSpaces()
+		if parser.done() || parser.peek() != '.' {
+			return key, nil
+		}
+		parser.pos++
+		parser.skipSpaces()
+	}
+}
+
+func (parser *tomlParser) keyPart() (string, error) {
+	if parser.done() {
+		return "", parser.errorf("expected a key")
+	}
+
+	switch parser.peek() {
+	case '"':
+		return parser.basicString()
+	case '\'':
+		return parser.literalString()
+	}
+
+	start := parser.pos
+	for !parser.done() && isBareKeyChar(rune(parser.peek())) {
+		parser.pos++
+	}
+	if start == parser.pos {
+		return "", parser.errorf("unexpected %q, expected a key", parser.peek())
+	}
+
+	return parser.input[start:parser.pos], nil
+}
+
+// Reads a string, integer, boolean or array.
+func (parser *tomlParser) value() (any, error) {
+	if parser.done() || parser.peek() == '\n' || parser.peek() == '#' {
+		return nil, parser.errorf("expected a value")
+	}
+
+	rest := parser.input[parser.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`), strings.HasPrefix(rest, "'''"):
+		return nil, parser.errorf("multi-line strings are not supported")
+	case rest[0] == '"':
+		return parser.basicString()
+	case rest[0] == '\'':
+		return parser.literalString()
+	case rest[0] == '[':
+		return parser.array()
+	case rest[0] == '{':
+		return nil, parser.errorf("inline tables are not supported")
+	}
+
+	start := parser.pos
+	for !parser.done() && !strings.ContainsRune(" \t\r\n#,]", rune(parser.peek())) {
+		parser.pos++
+	}
+	word := parser.input[start:parser.pos]
+
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	if strings.Contains(word, "__") || strings.HasPrefix(word, "_") || strings.HasSuffix(word, "_") {
+		return nil, parser.errorf("invalid value %q", word)
+	}
+	number, err := strconv.ParseInt(strings.ReplaceAll(word, "_", ""), 10, 64)
+	if err != nil {
+		return nil, parser.errorf("invalid value %q, expected a string, integer, boolean or array", word)
+	}
+
+	return number, nil
+}
+
+func (parser *tomlParser) array() ([]any, error) {
+	parser.pos++
+
+	values := []any{}
+	for {
+		parser.skipBlank()
+		if parser.done() {
+			return nil, parser.errorf("unterminated array")
+		}
+		if parser.peek() == ']' {
+			parser.pos++
+			return values, nil
+		}
+
+		value, err := parser.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		parser.skipBlank()
+		if parser.done() {
+			return nil, parser.errorf("unterminated array")
+		}
+		switch parser.peek() {
+		case ',':
+			parser.pos++
+		case ']':
+		default:
+			return nil, parser.errorf("expected , or ] in array, got %q", parser.peek())
+		}
+	}
+}
+
+func (parser *tomlParser) literalString() (string, error) {
+	parser.pos++
+
+	end := strings.IndexAny(parser.input[parser.pos:], "'\n")
+	if end < 0 || parser.input[parser.pos+end] == '\n' {
+		return "", parser.errorf("unterminated string")
+	}
+
+	text := parser.input[parser.pos : parser.pos+end]
+	parser.pos += end + 1
+	return text, nil
+}
+
+func (parser *tomlParser) basicString() (string, error) {
+	parser.pos++
+
+	var text strings.Builder
+	for {
+		if parser.done() || parser.peek() == '\n' {
+			return "", parser.errorf("unterminated string")
+		}
+
+		c := parser.peek()
+		parser.pos++
+
+		switch c {
+		case '"':
+			return text.String(), nil
+		case '\\':
+		default:
+			text.WriteByte(c)
+			continue
+		}
+
+		if parser.done() {
+			return "", parser.errorf("unterminated string")
+		}
+		escape := parser.peek()
+		parser.pos++
+
+		switch escape {
+		case '"', '\\':
+			text.WriteByte(escape)
+		case 'b':
+			text.WriteByte('\b')
+		case 't':
+			text.WriteByte('\t')
+		case 'n':
+			text.WriteByte('\n')
+		case 'f':
+			text.WriteByte('\f')
+		case 'r':
+			text.WriteByte('\r')
+		case 'u', 'U':
+			size := 4
+			if escape == 'U' {
+				size = 8
+			}
+			if parser.pos+size > len(parser.input) {
+				return "", parser.errorf("invalid unicode escape")
+			}
+
+			code, err := strconv.ParseUint(parser.input[parser.pos:parser.pos+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", parser.errorf("invalid unicode escape \\%c%s", escape, parser.input[parser.pos:parser.pos+size])
+			}
+			parser.pos += size
+			text.WriteRune(rune(code))
+		default:
+			return "", parser.errorf("invalid escape \\%c", escape)
+		}
+	}
+}
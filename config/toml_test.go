@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	document := `# fem-helper
+output_dir = "~/vault/courses" # trailing comment
+tags = [
+  "fem",
+  'go', # literal
+]
+
+[naming]
+pad = 1_000
+flat = true
+"lesson_name" = "{{pad .Index}} \"{{.Title}}\"\té"
+
+[profiles.work-vault]
+naming.one_based = false
+`
+
+	entries, err := parseTOML([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []entry{
+		{key: []string{"output_dir"}, value: "~/vault/courses", line: 2},
+		{key: []string{"tags"}, value: []any{"fem", "go"}, line: 3},
+		{key: []string{"naming", "pad"}, value: int64(1000), line: 9},
+		{key: []string{"naming", "flat"}, value: true, line: 10},
+		{key: []string{"naming", "lesson_name"}, value: "{{pad .Index}} \"{{.Title}}\"\té", line: 11},
+		{key: []string{"profiles", "work-vault", "naming", "one_based"}, value: false, line: 14},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %v, want %v", entries, want)
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	errorTests := []struct {
+		name     string
+		document string
+		line     int
+	}{
+		{"missing value", "a =\n", 1},
+		{"missing equals", "a \"b\"\n", 1},
+		{"duplicate key", "a = 1\n\na = 2\n", 3},
+		{"duplicate table", "[a]\n[b]\n[a]\n", 3},
+		{"unterminated string", "a = \"b\nc = 1\n", 1},
+		{"unterminated array", "a = [1,\n2\n", 3},
+		{"invalid escape", `a = "\q"`, 1},
+		{"float", "a = 1.5\n", 1},
+		{"inline table", "a = {b = 1}\n", 1},
+		{"array of tables", "[[a]]\n", 1},
+		{"value after value", "a = 1 2\n", 1},
+	}
+
+	for _, c := range errorTests {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseTOML([]byte(c.document))
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("got %v, want a SyntaxError", err)
+			}
+			if syntaxErr.Line != c.line {
+				t.Errorf("got line %d, want %d", syntaxErr.Line, c.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+// Package description converts the HTML of course and lesson
+// descriptions to markdown, org or plain text.
+package description
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/raphaeltannous/fem-helper/markup"
+)
+
+// Format is an output format of Convert.
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	Org      Format = "org"
+	Text     Format = "text"
+)
+
+var Formats = []Format{Markdown, Org, Text}
+
+// Options configures Convert.
+type Options struct {
+	Format Format
+	// URL relative links are resolved against, they are kept as they are
+	// if it is empty.
+	BaseURL string
+}
+
+// Inline markers of a Format.
+type markers struct {
+	emphasis, strong, code string
+}
+
+var formatMarkers = map[Format]markers{
+	Markdown: {"*", "**", "`"},
+	Org:      {"/", "*", "~"},
+	Text:     {"", "", ""},
+}
+
+// Returns the HTML subset of descriptions, text as paragraphs, line
+// breaks, links, emphasis, lists and code, converted to options.Format.
+// Other tags are dropped, keeping their text except for scripts and
+// styles, and entities are decoded. Only http, https, mailto and relative
+// links are kept, other links are replaced by their text.
+// Text without tags is kept as it is, with its blank lines separating
+// paragraphs.
+func Convert(text string, options Options) (string, error) {
+	if !slices.Contains(Formats, options.Format) {
+		return "", fmt.Errorf("unknown description format %q", options.Format)
+	}
+
+	var base *url.URL
+	if options.BaseURL != "" {
+		parsed, err := url.Parse(options.BaseURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid base URL: %w", err)
+		}
+		base = parsed
+	}
+
+	converter := converter{
+		format:  options.Format,
+		markers: formatMarkers[options.Format],
+		base:    base,
+	}
+	for _, token := range tokenize(text) {
+		converter.token(token)
+	}
+	converter.flush()
+
+	return converter.String(), nil
+}
+
+// Tags that start and end a block of their own.
+var blockTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true,
+	"footer": true, "blockquote": true, "table": true, "tr": true, "hr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// Tags whose content is dropped along with them.
+var droppedTags = map[string]bool{"script": true, "style": true}
+
+// Schemes of the links that are kept, "" for relative links.
+var linkSchemes = map[string]bool{"": true, "http": true, "https": true, "mailto": true}
+
+// Blank lines separating paragraphs in text.
+var paragraphBreak = regexp.MustCompile(`\n[ \t\r]*\n`)
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+type block struct {
+	text   string
+	inList bool
+}
+
+type list struct {
+	ordered bool
+	next    int
+	// Prefixes of the item the list is nested in, restored when the list
+	// ends.
+	prefix, continuation string
+}
+
+type link struct {
+	href  string
+	start int
+}
+
+type converter struct {
+	format  Format
+	markers markers
+	base    *url.URL
+
+	blocks []block
+	inline strings.Builder
+
+	// Prefix of the first line of the next block, and of its other lines.
+	prefix, continuation string
+
+	lists []list
+	links []link
+	code  int
+
+	pre     bool
+	preText strings.Builder
+
+	// Name of the dropped tag whose content is being skipped.
+	dropped string
+}
+
+func (converter *converter) token(token token) {
+	if converter.dropped != "" {
+		if token.kind == endTagToken && token.data == converter.dropped {
+			converter.dropped = ""
+		}
+		return
+	}
+
+	if converter.pre {
+		switch {
+		case token.kind == endTagToken && token.data == "pre":
+			converter.endPre()
+		case token.kind == textToken:
+			converter.preText.WriteString(token.data)
+		}
+		return
+	}
+
+	switch token.kind {
+	case textToken:
+		converter.text(token.data)
+	case startTagToken:
+		converter.startTag(token)
+	case endTagToken:
+		converter.endTag(token.data)
+	}
+}
+
+func (converter *converter) text(text string) {
+	for x, paragraph := range paragraphBreak.Split(text, -1) {
+		if x > 0 {
+			converter.flush()
+		}
+
+		paragraph = whitespace.ReplaceAllString(paragraph, " ")
+		if converter.format == Markdown && converter.code == 0 {
+			paragraph = markup.EscapeMarkdown(paragraph)
+		}
+		converter.inline.WriteString(paragraph)
+	}
+}
+
+func (converter *converter) startTag(tag token) {
+	switch name := tag.data; {
+	case droppedTags[name]:
+		converter.dropped = name
+	case blockTags[name]:
+		converter.flush()
+		if len(name) == 2 && name[0] == 'h' {
+			converter.inline.WriteString(converter.markers.strong)
+		}
+	case name == "br":
+		converter.inline.WriteString("\n")
+	case name == "em", name == "i":
+		converter.inline.WriteString(converter.markers.emphasis)
+	case name == "strong", name == "b":
+		converter.inline.WriteString(converter.markers.strong)
+	case name == "code", name == "kbd", name == "samp":
+		converter.inline.WriteString(converter.markers.code)
+		converter.code++
+	case name == "a":
+		converter.links = append(converter.links, link{
+			href:  converter.resolve(tag.attrs["href"]),
+			start: converter.inline.Len(),
+		})
+	case name == "ul", name == "ol":
+		converter.flush()
+		converter.lists = append(converter.lists, list{
+			ordered:      name == "ol",
+			next:         1,
+			prefix:       converter.continuation,
+			continuation: converter.continuation,
+		})
+	case name == "li":
+		converter.startItem()
+	case name == "pre":
+		converter.flush()
+		converter.pre = true
+		converter.preText.Reset()
+	}
+}
+
+func (converter *converter) endTag(name string) {
+	switch {
+	case blockTags[name]:
+		if len(name) == 2 && name[0] == 'h' {
+			converter.closeMarker(converter.markers.strong)
+		}
+		converter.flush()
+	case name == "em", name == "i":
+		converter.closeMarker(converter.markers.emphasis)
+	case name == "strong", name == "b":
+		converter.closeMarker(converter.markers.strong)
+	case name == "code", name == "kbd", name == "samp":
+		if converter.code > 0 {
+			converter.code--
+			converter.closeMarker(converter.markers.code)
+		}
+	case name == "a":
+		converter.endLink()
+	case name == "ul", name == "ol":
+		converter.flush()
+		if len(converter.lists) > 0 {
+			ended := converter.lists[len(converter.lists)-1]
+			converter.lists = converter.lists[:len(converter.lists)-1]
+			converter.prefix, converter.continuation = ended.continuation, ended.continuation
+		}
+	case name == "li":
+		converter.flush()
+	}
+}
+
+// Starts a list item, whose marker prefixes its first line.
+func (converter *converter) startItem() {
+	converter.flush()
+
+	if len(converter.lists) == 0 {
+		converter.lists = append(converter.lists, list{next: 1})
+	}
+	current := &converter.lists[len(converter.lists)-1]
+
+	marker := "- "
+	if current.ordered {
+		marker = fmt.Sprintf("%d. ", current.next)
+		current.next++
+	}
+
+	converter.prefix = current.prefix + marker
+	converter.continuation = current.prefix + strings.Repeat(" ", len(marker))
+}
+
+// Closes an inline marker, before the spaces that end its text so that
+// markdown and org still read it.
+func (converter *converter) closeMarker(marker string) {
+	if marker == "" {
+		return
+	}
+
+	text := converter.inline.String()
+	trimmed := strings.TrimRight(text, " ")
+
+	converter.inline.Reset()
+	converter.inline.WriteString(trimmed + marker + text[len(trimmed):])
+}
+
+func (converter *converter) endLink() {
+	if len(converter.links) == 0 {
+		return
+	}
+	ended := converter.links[len(converter.links)-1]
+	converter.links = converter.links[:len(converter.links)-1]
+
+	text := converter.inline.String()
+	if ended.start > len(text) {
+		return
+	}
+	before, label := text[:ended.start], strings.TrimSpace(text[ended.start:])
+
+	if ended.href == "" {
+		return
+	}
+	if label == "" {
+		label = ended.href
+		if converter.format == Markdown {
+			label = markup.EscapeMarkdown(label)
+		}
+	}
+
+	var formatted string
+	switch converter.format {
+	case Markdown:
+		formatted = fmt.Sprintf("[%s](%s)", label, markup.EscapeURL(ended.href))
+	case Org:
+		formatted = fmt.Sprintf("[[%s][%s]]", ended.href, label)
+	default:
+		formatted = label
+		if label != ended.href {
+			formatted = fmt.Sprintf("%s (%s)", label, ended.href)
+		}
+	}
+
+	converter.inline.Reset()
+	converter.inline.WriteString(before + formatted)
+}
+
+// Resolves href against the base URL. Returns "" for links that are not
+// kept, such as javascript: links.
+func (converter *converter) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(href)
+	if err != nil || !linkSchemes[parsed.Scheme] {
+		return ""
+	}
+
+	if converter.base == nil {
+		return href
+	}
+
+	return converter.base.ResolveReference(parsed).String()
+}
+
+func (converter *converter) endPre() {
+	converter.pre = false
+
+	text := strings.TrimRight(strings.TrimLeft(converter.preText.String(), "\n"), " \t\r\n")
+	if text == "" {
+		return
+	}
+
+	switch converter.format {
+	case Markdown:
+		fence := "```"
+		if strings.Contains(text, fence) {
+			fence = "~~~~"
+		}
+		text = fence + "\n" + text + "\n" + fence
+	case Org:
+		text = "#+begin_example\n" + text + "\n#+end_example"
+	default:
+		text = markup.Indent(text, "    ")
+	}
+
+	converter.addBlock(strings.Split(text, "\n"))
+}
+
+// Ends the current block, if it has any text.
+func (converter *converter) flush() {
+	text := converter.inline.String()
+	converter.inline.Reset()
+
+	// Inline markers can not span blocks.
+	converter.code = 0
+
+	var lines []string
+	for line := range strings.SplitSeq(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) > 0 {
+		converter.addBlock(lines)
+	}
+}
+
+func (converter *converter) addBlock(lines []string) {
+	for x := range lines {
+		if x == 0 {
+			lines[x] = converter.prefix + lines[x]
+		} else {
+			lines[x] = converter.continuation + lines[x]
+		}
+	}
+
+	converter.blocks = append(converter.blocks, block{
+		text:   strings.Join(lines, "\n"),
+		inList: len(converter.lists) > 0,
+	})
+
+	// The next blocks of a list item are aligned with its text.
+	converter.prefix = converter.continuation
+}
+
+// Returns the blocks separated by blank lines, the consecutive blocks of
+// lists by a single newline.
+func (converter *converter) String() string {
+	var result strings.Builder
+
+	for x, block := range converter.blocks {
+		if x > 0 {
+			if block.inList && converter.blocks[x-1].inList {
+				result.WriteString("\n")
+			} else {
+				result.WriteString("\n\n")
+			}
+		}
+		result.WriteString(block.text)
+	}
+
+	return result.String()
+}
@@ -0,0 +1,97 @@
+package description
+
+import (
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	const html = `<p>Learn <strong>Go</strong> &amp; <em>build </em>APIs with <code>net/http</code>.</p>
+<p>See <a href="/courses/go/">the course</a>.<br>Then practice:</p>
+<ul>
+  <li>Structs</li>
+  <li>Maps<ol><li>make</li><li>delete</li></ol></li>
+</ul>
+<pre><code>go run .
+</code></pre>`
+
+	convertTests := []struct {
+		format Format
+		want   string
+	}{
+		{
+			Markdown,
+			"Learn **Go** & *build* APIs with `net/http`.\n\n" +
+				"See [the course](https://frontendmasters.com/courses/go/).\nThen practice:\n\n" +
+				"- Structs\n- Maps\n  1. make\n  2. delete\n\n" +
+				"```\ngo run .\n```",
+		},
+		{
+			Org,
+			"Learn *Go* & /build/ APIs with ~net/http~.\n\n" +
+				"See [[https://frontendmasters.com/courses/go/][the course]].\nThen practice:\n\n" +
+				"- Structs\n- Maps\n  1. make\n  2. delete\n\n" +
+				"#+begin_example\ngo run .\n#+end_example",
+		},
+		{
+			Text,
+			"Learn Go & build APIs with net/http.\n\n" +
+				"See the course (https://frontendmasters.com/courses/go/).\nThen practice:\n\n" +
+				"- Structs\n- Maps\n  1. make\n  2. delete\n\n" +
+				"    go run .",
+		},
+	}
+
+	for _, c := range convertTests {
+		t.Run(string(c.format), func(t *testing.T) {
+			got, err := Convert(html, Options{Format: c.format, BaseURL: "https://frontendmasters.com/"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != c.want {
+				t.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestConvertEdgeCases(t *testing.T) {
+	edgeTests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain text", "First line\nsame paragraph.\n\nSecond #1 paragraph.", "First line same paragraph.\n\nSecond \\#1 paragraph."},
+		{"entities", "Tom &lt;3 Jerry&nbsp;&#39;s", "Tom \\<3 Jerry\u00a0's"},
+		{"comments and unknown tags", "<!-- x --><span class=\"a\">kept</span> <img src=x>", "kept"},
+		{"stray less than", "a < b", "a \\< b"},
+		{"absolute link", `<a href='https://example.com/a b'>example</a>`, "[example](https://example.com/a%20b)"},
+		{"empty link text", `<a href="https://example.com"></a>`, "[https://example.com](https://example.com)"},
+		{"relative link", `<a href="/courses/">courses</a>`, "[courses](/courses/)"},
+		{"mailto link", `<a href="mailto:team@example.com">mail</a>`, "[mail](mailto:team@example.com)"},
+		{"javascript link", `<a href=" JavaScript:alert(1)">click</a> me`, "click me"},
+		{"data link", `<a href="data:text/html;base64,PHNjcmlwdD4=">open</a>`, "open"},
+		{"invalid link", "<a href=\"java\tscript:alert(1)\">click</a>", "click"},
+		{"script and style", `<p>a<script>if (1 < 2) alert("</p>")</script>b</p><style>p { color: red }</style>`, "ab"},
+		{"unclosed tags", "<p>one<p>two<ul><li>a<li>b", "one\n\ntwo\n\n- a\n- b"},
+		{"heading", "<h2>Setup</h2><p>Text</p>", "**Setup**\n\nText"},
+		{"empty", "", ""},
+	}
+
+	for _, c := range edgeTests {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Convert(c.text, Options{Format: Markdown})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+
+	if _, err := Convert("", Options{Format: "rst"}); err == nil {
+		t.Error("got no error for an unknown format")
+	}
+}
@@ -0,0 +1,172 @@
+package description
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	startTagToken
+	endTagToken
+)
+
+type token struct {
+	kind tokenKind
+	// Unescaped text of text tokens, lower case name of tags.
+	data  string
+	attrs map[string]string
+}
+
+// Splits text into HTML tokens. Comments, doctypes and processing
+// instructions are dropped, and a "<" that does not start a tag is kept
+// as text.
+func tokenize(text string) []token {
+	var (
+		tokens  []token
+		pending strings.Builder
+	)
+
+	flush := func() {
+		if pending.Len() > 0 {
+			tokens = append(tokens, token{kind: textToken, data: html.UnescapeString(pending.String())})
+			pending.Reset()
+		}
+	}
+
+	for len(text) > 0 {
+		start := strings.IndexByte(text, '<')
+		if start == -1 {
+			pending.WriteString(text)
+			break
+		}
+
+		pending.WriteString(text[:start])
+		text = text[start:]
+
+		if strings.HasPrefix(text, "<!--") {
+			flush()
+			end := strings.Index(text, "-->")
+			if end == -1 {
+				break
+			}
+			text = text[end+len("-->"):]
+			continue
+		}
+
+		if len(text) > 1 && (text[1] == '!' || text[1] == '?') {
+			flush()
+			end := strings.IndexByte(text, '>')
+			if end == -1 {
+				break
+			}
+			text = text[end+1:]
+			continue
+		}
+
+		tag, rest, ok := parseTag(text)
+		if !ok {
+			pending.WriteByte('<')
+			text = text[1:]
+			continue
+		}
+
+		flush()
+		tokens = append(tokens, tag)
+		text = rest
+	}
+	flush()
+
+	return tokens
+}
+
+// Parses the tag text starts with, returning the text after it.
+func parseTag(text string) (token, string, bool) {
+	tag := token{kind: startTagToken}
+
+	position := 1
+	if position < len(text) && text[position] == '/' {
+		tag.kind = endTagToken
+		position++
+	}
+
+	nameStart := position
+	for position < len(text) && isNameByte(text[position]) {
+		position++
+	}
+	if position == nameStart || !isLetter(text[nameStart]) {
+		return token{}, text, false
+	}
+	tag.data = strings.ToLower(text[nameStart:position])
+
+	end := strings.IndexByte(text[position:], '>')
+	if end == -1 {
+		return token{}, text, false
+	}
+
+	if tag.kind == startTagToken {
+		tag.attrs = parseAttributes(strings.TrimSuffix(text[position:position+end], "/"))
+	}
+
+	return tag, text[position+end+1:], true
+}
+
+// Parses the attributes of a start tag, such as ` href="/a" target=_blank`.
+func parseAttributes(text string) map[string]string {
+	attrs := make(map[string]string)
+
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			return attrs
+		}
+
+		nameEnd := strings.IndexFunc(text, func(char rune) bool {
+			return unicode.IsSpace(char) || char == '='
+		})
+		if nameEnd == -1 {
+			attrs[strings.ToLower(text)] = ""
+			return attrs
+		}
+
+		name := strings.ToLower(text[:nameEnd])
+		text = strings.TrimLeftFunc(text[nameEnd:], unicode.IsSpace)
+
+		if !strings.HasPrefix(text, "=") {
+			attrs[name] = ""
+			continue
+		}
+		text = strings.TrimLeftFunc(text[1:], unicode.IsSpace)
+
+		var value string
+		if text != "" && (text[0] == '"' || text[0] == '\'') {
+			quote := text[0]
+			end := strings.IndexByte(text[1:], quote)
+			if end == -1 {
+				value, text = text[1:], ""
+			} else {
+				value, text = text[1:end+1], text[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(text, unicode.IsSpace)
+			if end == -1 {
+				value, text = text, ""
+			} else {
+				value, text = text[:end], text[end:]
+			}
+		}
+
+		attrs[name] = html.UnescapeString(value)
+	}
+}
+
+func isLetter(char byte) bool {
+	return 'a' <= char && char <= 'z' || 'A' <= char && char <= 'Z'
+}
+
+func isNameByte(char byte) bool {
+	return isLetter(char) || '0' <= char && char <= '9' || char == '-'
+}
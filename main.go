@@ -1,18 +1,24 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/naming"
 	"github.com/raphaeltannous/fem-helper/outputdir"
+	"github.com/raphaeltannous/fem-helper/tag"
 	"github.com/raphaeltannous/fem-helper/templater"
+	"github.com/raphaeltannous/fem-helper/yaml"
 )
 
 var (
@@ -22,19 +28,15 @@ var (
 
 func init() {
 	const (
-		outputDirHelpString  = "Output directory of the course."
+		outputDirHelpString  = "Output directory of the course, or a .zip/.tar.gz archive to write it to."
 		courseSlugHelpString = "Slug of the course."
 	)
 
-	flag.StringVar(&courseSlug, "course-slug", "", courseSlugHelpString+" (required)")
-	flag.StringVar(&courseSlug, "c", "", courseSlugHelpString+" (shorthand/required)")
+	generateFlags.StringVar(&courseSlug, "course-slug", "", courseSlugHelpString+" (required)")
+	generateFlags.StringVar(&courseSlug, "c", "", courseSlugHelpString+" (shorthand/required)")
 
-	flag.StringVar(&outputDir, "output-dir", "", outputDirHelpString)
-	flag.StringVar(&outputDir, "o", "", outputDirHelpString+" (shorthand)")
-
-	// todo:
-	// implement flag
-	// --clean will clean cache
+	generateFlags.StringVar(&outputDir, "output-dir", "", outputDirHelpString)
+	generateFlags.StringVar(&outputDir, "o", "", outputDirHelpString+" (shorthand)")
 }
 
 type customUserTemplates []string
@@ -45,43 +47,34 @@ func (cUT *customUserTemplates) String() string {
 
 func (cUT *customUserTemplates) Set(path string) error {
 	value := filepath.Base(path)
-	if slices.Contains([]string{"course.tmpl", "lesson.tmpl"}, value) {
+	if slices.Contains(templater.TemplateNames, value) {
 		*cUT = append(*cUT, path)
 		return nil
 	}
 
-	return errors.New("allowed template filenames: course.tmpl and lesson.tmpl")
+	return fmt.Errorf("allowed template filenames: %s", strings.Join(templater.TemplateNames, ", "))
 }
 
-func (cUT *customUserTemplates) checkTemplates() {
-	defaultTemplatePath := "templates/obsidian/"
-
-	seen := make(map[string]bool)
+// Returns the custom template paths by template name.
+func (cUT *customUserTemplates) byName() map[string]string {
+	templates := make(map[string]string)
 	for _, templatePath := range *cUT {
-		seen[filepath.Base(templatePath)] = true
+		templates[filepath.Base(templatePath)] = templatePath
 	}
 
-	for _, templateName := range []string{"course.tmpl", "lesson.tmpl"} {
-		if !seen[templateName] {
-			*cUT = append(*cUT, filepath.Join(defaultTemplatePath, templateName))
-		}
-	}
+	return templates
 }
 
-func (cUT *customUserTemplates) getTemplateByName(name string) string {
-	for _, templatePath := range *cUT {
-		if name == filepath.Base(templatePath) {
-			return templatePath
-		}
-	}
-
-	return ""
-}
-
-var customTemplates customUserTemplates
+var (
+	customTemplates customUserTemplates
+	sectionNotes    bool
+	flavor          string
+)
 
 func init() {
-	flag.Var(&customTemplates, "custom-template", "Custom templates for course and lesson. (Allowed filenames: course.tmpl and lesson.tmpl)")
+	generateFlags.StringVar(&flavor, "flavor", templater.DefaultFlavor, fmt.Sprintf("Flavor of the default templates. (one of: %s)", strings.Join(templater.Flavors, ", ")))
+	generateFlags.Var(&customTemplates, "custom-template", fmt.Sprintf("Custom template, named after the template it replaces. (repeatable, allowed filenames: %s)", strings.Join(templater.TemplateNames, ", ")))
+	generateFlags.BoolVar(&sectionNotes, "section-notes", false, "Generate a note for each section. (enabled by a custom section.tmpl)")
 }
 
 type tags []string
@@ -95,63 +88,268 @@ func (t *tags) Set(value string) error {
 		return errors.New("tags flag already set.")
 	}
 
-	if len(value) == 0 {
-		return errors.New("tags flag cannot be an empty string.")
+	parsed, err := tag.ParseList(value)
+	if err != nil {
+		return err
 	}
 
-	for tag := range strings.SplitSeq(value, ",") {
-		*t = append(*t, tag)
+	if len(parsed) == 0 {
+		return errors.New("tags flag cannot be empty.")
 	}
 
+	*t = parsed
 	return nil
 }
 
-var tagsFlag tags
+var (
+	tagsFlag    tags
+	tagPrefix   string
+	sectionTags bool
+)
 
 func init() {
 	var (
-		tagsFlagHelp = "comma-seperated list of tags."
+		tagsFlagHelp = "comma-seperated list of tags. Spaces become hyphens and leading # are dropped."
 	)
 
-	flag.Var(&tagsFlag, "tags", tagsFlagHelp)
-	flag.Var(&tagsFlag, "t", tagsFlagHelp+" (shorthand)")
+	generateFlags.Var(&tagsFlag, "tags", tagsFlagHelp)
+	generateFlags.Var(&tagsFlag, "t", tagsFlagHelp+" (shorthand)")
+
+	generateFlags.StringVar(&tagPrefix, "tag-prefix", templater.DefaultTagPrefix, "Prefix of the course tag <prefix>/<course slug>. (empty for no prefix)")
+	generateFlags.BoolVar(&sectionTags, "section-tags", false, "Tag lessons with <prefix>/<course slug>/<section slug> instead of the course tag.")
+}
+
+// fileMode is a flag.Value for octal file modes, such as 0644.
+type fileMode struct {
+	mode *os.FileMode
+}
+
+func (fM fileMode) String() string {
+	if fM.mode == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%#o", *fM.mode)
+}
+
+func (fM fileMode) Set(value string) error {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode > 0777 {
+		return fmt.Errorf("%q is not an octal permission mode such as 0644", value)
+	}
+
+	*fM.mode = os.FileMode(mode)
+	return nil
+}
+
+var outputOptions = outputdir.DefaultOptions()
+
+func init() {
+	generateFlags.Var(fileMode{&outputOptions.FileMode}, "file-mode", "Permission mode of new files. Existing files keep their mode.")
+	generateFlags.Var(fileMode{&outputOptions.DirMode}, "dir-mode", "Permission mode of new directories. Existing directories keep their mode.")
+}
+
+var namingScheme = naming.DefaultScheme()
+
+func init() {
+	generateFlags.StringVar(&namingScheme.SectionName, "section-name", naming.DefaultSectionName, "Template of the section folder names.")
+	generateFlags.StringVar(&namingScheme.LessonName, "lesson-name", naming.DefaultLessonName, "Template of the lesson file names, without the .md extension.")
+	generateFlags.BoolVar(&namingScheme.OneBased, "one-based", false, "Number sections and lessons starting from 1.")
+	generateFlags.IntVar(&namingScheme.Padding, "pad", 0, "Zero pad numbers to this width in names.")
+	generateFlags.BoolVar(&namingScheme.Flat, "flat", false, "Do not create section folders.")
+	generateFlags.BoolVar(&namingScheme.TitleNames, "title-names", false, "Use titles instead of slugs in names.")
+
+	generateFlags.IntVar(&namingScheme.Slug.MaxLength, "slug-max-length", 0, "Maximum length of section and lesson slugs. (0 for no maximum)")
+	generateFlags.BoolVar(&namingScheme.Slug.ASCII, "slug-ascii", false, "Drop the characters that can not be transliterated to ASCII from slugs.")
+	generateFlags.Func("slug-replace", "Replacement applied to titles before slugifying them, as from=to. (repeatable)", func(value string) error {
+		from, to, ok := strings.Cut(value, "=")
+		if !ok || from == "" {
+			return errors.New("slug replacements must be given as from=to")
+		}
+
+		namingScheme.Slug.Replacements[from] = to
+		return nil
+	})
+}
+
+var annotationOptions = templater.DefaultAnnotationOptions()
+
+func init() {
+	generateFlags.StringVar(&annotationOptions.Style, "annotation-style", annotationOptions.Style, fmt.Sprintf("How annotations are rendered. (one of: %s)", strings.Join(templater.AnnotationStyles, ", ")))
+	generateFlags.StringVar(&annotationOptions.Callout, "callout-type", annotationOptions.Callout, "Obsidian callout type of the callout annotation style, such as NOTE, TIP or QUOTE.")
+	generateFlags.Func("timestamp-format", fmt.Sprintf("Format of the annotation times. (one of: %s)", timestampFormatNames()), func(value string) error {
+		format := api.TimestampFormat(value)
+		if err := format.Validate(); err != nil {
+			return err
+		}
+
+		annotationOptions.Timestamps = format
+		return nil
+	})
+	generateFlags.StringVar(&annotationOptions.Fold, "callout-fold", annotationOptions.Fold, `Folding of the callout annotation style: "+" expanded, "-" collapsed, or "" not foldable.`)
+	generateFlags.BoolVar(&annotationOptions.Merge, "merge-annotations", false, "Merge annotations with overlapping time ranges into one.")
+	generateFlags.BoolVar(&annotationOptions.Classify, "classify-callouts", false, "Pick the callout type of each annotation from its message: TIP, WARNING, INFO for links or EXAMPLE for code.")
+}
+
+var (
+	frontmatter  yaml.Map
+	progressView string
+	baseURL      string
+	checkSchema  bool
+)
+
+func init() {
+	generateFlags.Func("frontmatter", "Extra frontmatter property of every note, as key=value. Values are strings unless they are true, false, null or numbers. (repeatable)", func(value string) error {
+		key, text, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return errors.New("frontmatter properties must be given as key=value")
+		}
+
+		frontmatter.Set(key, yaml.ParseScalar(text))
+		return nil
+	})
+
+	generateFlags.StringVar(&baseURL, "base-url", templater.DefaultBaseURL, "Site the course, lesson and annotation links point to, such as a local stand-in.")
+	generateFlags.BoolVar(&checkSchema, "check-schema", false, "Warn when the course payload drifted from the expected model, see schema-check.")
+	generateFlags.StringVar(&progressView, "progress-view", "", fmt.Sprintf("Generate a progress table of the course next to its note. (one of: %s)", strings.Join(templater.ProgressViews, ", ")))
+}
+
+func timestampFormatNames() string {
+	names := make([]string, len(api.TimestampFormats))
+	for x, format := range api.TimestampFormats {
+		names[x] = string(format)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// Flags of the generate command, also accepted without a command.
+var generateFlags = flag.NewFlagSet("generate", flag.ExitOnError)
+
+var profile = profileFlag(generateFlags)
+
+func init() {
+	generateFlags.Usage = func() {
+		fmt.Fprintln(generateFlags.Output(), "Usage: fem-helper [generate] -c <course-slug> -o <output-dir> [flags]")
+		fmt.Fprintln(generateFlags.Output(), "\nGenerates the notes of a course.")
+		generateFlags.PrintDefaults()
+		fmt.Fprintln(generateFlags.Output(), "\nRun \"fem-helper help\" for the other commands.")
+	}
 }
 
 func main() {
-	flag.Parse()
+	args := os.Args[1:]
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if !strings.HasPrefix(args[0], "-") {
+		command, ok := lookupCommand(args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+			usage()
+			os.Exit(2)
+		}
+
+		if err := command.run(args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// The flags of generate, without a command.
+	if err := generateCommand(args); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	requiredFlags([][2]string{
+// Generates the notes of a course, see generateFlags.
+func generateCommand(args []string) error {
+	generateFlags.Parse(args)
+	if generateFlags.NArg() > 0 {
+		generateFlags.Usage()
+		os.Exit(2)
+	}
+
+	// Flags given on the command line win over the config.
+	loaded, err := loadConfig(*profile)
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(generateFlags, loaded); err != nil {
+		return err
+	}
+
+	requiredFlags(generateFlags, [][2]string{
 		{"course-slug", "c"},
 		{"output-dir", "o"},
 	})
-	customTemplates.checkTemplates()
 
 	course, err := api.NewCourse(courseSlug)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	outputDirectory, err := outputdir.NewOutputDirectory(outputDir)
+	if checkSchema {
+		schema, err := api.CheckCourseSchema(course.Slug)
+		if err != nil {
+			return err
+		}
+
+		if schema.Drift() {
+			log.Printf("warning: the API payload of %s drifted from the expected model, some data may be lost:\n%s", course.Slug, schema)
+		}
+	}
+
+	report := course.Validate()
+	for _, problem := range report.Problems {
+		log.Println(problem)
+	}
+	if report.Fatal() {
+		return fmt.Errorf("refusing to generate %s, run \"fem-helper validate %s\" for details", course.Slug, course.Slug)
+	}
+
+	output, err := outputdir.Open(outputDir, outputOptions)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	markdown := templater.NewMarkdownTemplater(
+	markdown, err := templater.NewMarkdownTemplater(
 		course,
-		outputDirectory,
-		tagsFlag,
-		customTemplates.getTemplateByName("course.tmpl"),
-		customTemplates.getTemplateByName("lesson.tmpl"),
+		output,
+		templater.Options{
+			Tags:            tagsFlag,
+			TagPrefix:       tagPrefix,
+			SectionTags:     sectionTags,
+			CustomTemplates: customTemplates.byName(),
+			Flavor:          flavor,
+			SectionNotes:    sectionNotes,
+			Naming:          namingScheme,
+			Annotations:     annotationOptions,
+			Frontmatter:     frontmatter,
+			ProgressView:    progressView,
+			BaseURL:         baseURL,
+			Warn: func(err error) {
+				log.Printf("warning: %v", err)
+			},
+		},
 	)
-
-	if err := markdown.GenerateCourseMarkdown(); err != nil {
-		log.Fatal(err)
+	if err != nil {
+		output.Rollback()
+		return err
 	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return markdown.GenerateCourseMarkdown(ctx)
 }
 
-func requiredFlags(requiredFlags [][2]string) {
+func requiredFlags(flagSet *flag.FlagSet, requiredFlags [][2]string) {
 	givenFlags := make(map[string]bool)
-	flag.Visit(func(f *flag.Flag) {
+	flagSet.Visit(func(f *flag.Flag) {
 		givenFlags[f.Name] = true
 	})
 
@@ -160,7 +358,8 @@ func requiredFlags(requiredFlags [][2]string) {
 			continue
 		}
 
-		fmt.Fprintf(os.Stderr, "%s flag is required.\n", duo[0])
+		fmt.Fprintf(os.Stderr, "%s flag is required.\n\n", duo[0])
+		flagSet.Usage()
 		os.Exit(2)
 	}
 }
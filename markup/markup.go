@@ -0,0 +1,54 @@
+// Package markup escapes and lays out the text written into notes.
+package markup
+
+import "strings"
+
+// Characters that start markdown or Obsidian formatting inline.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"`", "\\`",
+	"*", "\\*",
+	"_", "\\_",
+	"[", "\\[",
+	"]", "\\]",
+	"<", "\\<",
+	">", "\\>",
+	"#", "\\#",
+	"^", "\\^",
+	"|", "\\|",
+	"~", "\\~",
+	"=", "\\=",
+	"$", "\\$",
+)
+
+// Escapes the characters of text that markdown or Obsidian would read as
+// formatting, tags, links or math.
+func EscapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// Characters that would end the target of a markdown link.
+var urlEscaper = strings.NewReplacer(
+	" ", "%20",
+	"(", "%28",
+	")", "%29",
+	"<", "%3C",
+	">", "%3E",
+)
+
+// Encodes the characters of href that would end a markdown link target.
+func EscapeURL(href string) string {
+	return urlEscaper.Replace(href)
+}
+
+// Prefixes every line of text that is not empty with prefix.
+func Indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for x, line := range lines {
+		if line != "" {
+			lines[x] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,24 @@
+package markup
+
+import "testing"
+
+func TestMarkup(t *testing.T) {
+	markupTests := []struct {
+		name string
+		fn   func(string) string
+		text string
+		want string
+	}{
+		{"markdown", EscapeMarkdown, "a *b* [c] #d $e$", `a \*b\* \[c\] \#d \$e\$`},
+		{"url", EscapeURL, "https://example.com/a (b)", "https://example.com/a%20%28b%29"},
+		{"indent", func(text string) string { return Indent(text, "  ") }, "a\n\nb", "  a\n\n  b"},
+	}
+
+	for _, c := range markupTests {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.fn(c.text); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
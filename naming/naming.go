@@ -0,0 +1,293 @@
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/outputdir"
+	"github.com/raphaeltannous/fem-helper/slug"
+)
+
+const (
+	DefaultSectionName = "{{pad .Index}}-{{.Name}}"
+	DefaultLessonName  = "{{pad .Index}}-{{.Name}}"
+)
+
+// Scheme describes how the section folders and lesson files of a course
+// are named.
+//
+// SectionName and LessonName are text/template strings executed with an
+// Item. The ".md" extension is appended to lesson names.
+type Scheme struct {
+	SectionName string
+	LessonName  string
+
+	// Number sections and lessons starting from 1 instead of 0.
+	OneBased bool
+	// Default width used by pad when no width is given.
+	Padding int
+	// Write every lesson next to the course note, without section folders.
+	Flat bool
+	// Use titles instead of slugs for Item.Name.
+	TitleNames bool
+
+	// Options of the section and lesson slugs. Slugs are made unique
+	// across all sections, and across all lessons, of a course.
+	Slug slug.Options
+}
+
+// Item is the data passed to the SectionName and LessonName templates.
+type Item struct {
+	// Index of the section or lesson, 1-based if Scheme.OneBased is set.
+	Index int
+	Title string
+	Slug  string
+	// Slug or Title depending on Scheme.TitleNames.
+	Name string
+}
+
+// Layout holds the computed paths of a course, relative to the output root
+// and slash separated. Paths are not cleaned, so that they can be validated
+// before they are written.
+type Layout struct {
+	CourseFile string
+	// Section folders by position in course.Sections. Empty for flat layouts.
+	SectionDirs []string
+	// Unique section slugs by position in course.Sections.
+	SectionSlugs []string
+	// Section notes by position in course.Sections. They are named after
+	// their folder and written in it, or next to the course note for flat
+	// layouts.
+	SectionFiles []string
+	// Lesson files by lesson index.
+	LessonFiles map[int]string
+}
+
+func DefaultScheme() Scheme {
+	return Scheme{
+		SectionName: DefaultSectionName,
+		LessonName:  DefaultLessonName,
+		Slug:        slug.DefaultOptions(),
+	}
+}
+
+// Returns index as numbered by the scheme.
+func (scheme Scheme) Number(index int) int {
+	if scheme.OneBased {
+		return index + 1
+	}
+
+	return index
+}
+
+// Computes the Layout of course.
+func (scheme Scheme) Layout(course api.CourseData) (Layout, error) {
+	sectionName, err := scheme.parse("section-name", scheme.SectionName, DefaultSectionName)
+	if err != nil {
+		return Layout{}, err
+	}
+
+	lessonName, err := scheme.parse("lesson-name", scheme.LessonName, DefaultLessonName)
+	if err != nil {
+		return Layout{}, err
+	}
+
+	layout := Layout{
+		CourseFile:   course.Slug + ".md",
+		SectionDirs:  make([]string, len(course.Sections)),
+		SectionSlugs: make([]string, len(course.Sections)),
+		SectionFiles: make([]string, len(course.Sections)),
+		LessonFiles:  make(map[int]string),
+	}
+
+	sectionSlugs := slug.NewDeduper()
+	lessonSlugs := slug.NewDeduper()
+	files := newFileSet()
+	files.unique(layout.CourseFile)
+
+	for x, section := range course.Sections {
+		sectionSlug := sectionSlugs.Unique(slug.Make(section.Title, scheme.Slug))
+		layout.SectionSlugs[x] = sectionSlug
+
+		name, err := scheme.execute(sectionName, section.Title, sectionSlug, x)
+		if err != nil {
+			return Layout{}, fmt.Errorf("section %d (%s): %w", x, section.Title, err)
+		}
+
+		sectionDir := ""
+		if !scheme.Flat {
+			sectionDir = name
+		}
+		layout.SectionDirs[x] = sectionDir
+
+		layout.SectionFiles[x] = name + ".md"
+		if sectionDir != "" {
+			layout.SectionFiles[x] = sectionDir + "/" + layout.SectionFiles[x]
+		}
+
+		for _, lesson := range course.Section(x) {
+			lessonIndex := lesson.Index
+			lessonSlug := lessonSlugs.Unique(slug.Make(lesson.Slug, scheme.Slug))
+
+			lessonFile, err := scheme.execute(lessonName, lesson.Title, lessonSlug, lessonIndex)
+			if err != nil {
+				return Layout{}, fmt.Errorf("lesson %d (%s): %w", lessonIndex, lesson.Slug, err)
+			}
+
+			if sectionDir != "" {
+				lessonFile = sectionDir + "/" + lessonFile
+			}
+			layout.LessonFiles[lessonIndex] = files.unique(lessonFile + ".md")
+		}
+	}
+
+	// Section notes are only named once every lesson is, so that lessons
+	// keep their names when a section note would collide with one.
+	for x := range layout.SectionFiles {
+		layout.SectionFiles[x] = files.unique(layout.SectionFiles[x])
+	}
+
+	return layout, nil
+}
+
+// Returns filePath, made unique among the note paths of the layout the
+// same way note paths are.
+func (layout Layout) Unique(filePath string) string {
+	files := newFileSet()
+	files.unique(layout.CourseFile)
+	for _, sectionFile := range layout.SectionFiles {
+		files.unique(sectionFile)
+	}
+	for _, lessonFile := range layout.LessonFiles {
+		files.unique(lessonFile)
+	}
+
+	return files.unique(filePath)
+}
+
+// fileSet makes note paths unique, ignoring case since not every file
+// system is case sensitive.
+type fileSet map[string]bool
+
+func newFileSet() fileSet {
+	return make(fileSet)
+}
+
+// Returns notePath, or notePath with -2, -3, ... before its extension if
+// it was already taken.
+func (files fileSet) unique(notePath string) string {
+	extension := path.Ext(notePath)
+	base := strings.TrimSuffix(notePath, extension)
+
+	unique := notePath
+	for x := 2; files[strings.ToLower(unique)]; x++ {
+		unique = fmt.Sprintf("%s-%d%s", base, x, extension)
+	}
+
+	files[strings.ToLower(unique)] = true
+	return unique
+}
+
+func (scheme Scheme) parse(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"pad": scheme.Pad,
+	}).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+func (scheme Scheme) execute(tmpl *template.Template, title, slug string, index int) (string, error) {
+	item := Item{
+		Index: scheme.Number(index),
+		Title: title,
+		Slug:  slug,
+		Name:  slug,
+	}
+	if scheme.TitleNames {
+		item.Name = sanitizeTitle(title)
+	}
+
+	for {
+		var result strings.Builder
+		if err := tmpl.Execute(&result, item); err != nil {
+			return "", err
+		}
+
+		name := strings.TrimSpace(result.String())
+		if name == "" {
+			return "", errors.New("name template produced an empty name")
+		}
+
+		// Long titles are shortened until the name, with the extension of
+		// its note, fits in a file name.
+		excess := len(name) + len(noteExtension) - outputdir.MaxNameLength
+		if !scheme.TitleNames || excess <= 0 || len(item.Name) <= 1 {
+			return name, nil
+		}
+
+		item.Name = sanitizeTitle(item.Name[:max(len(item.Name)-excess, 1)])
+	}
+}
+
+// Returns number zero padded to width, or to Scheme.Padding if no width
+// is given.
+func (scheme Scheme) Pad(number int, width ...int) string {
+	padding := scheme.Padding
+	if len(width) > 0 {
+		padding = width[0]
+	}
+
+	return fmt.Sprintf("%0*d", padding, number)
+}
+
+// Extension of the notes names are given to.
+const noteExtension = ".md"
+
+// Returns title as a portable file name: the characters that can not be
+// used in file names are replaced, control characters become spaces,
+// trailing dots and spaces are trimmed, and reserved names get a trailing
+// underscore. Titles with nothing left are named "untitled".
+func sanitizeTitle(title string) string {
+	name := strings.Map(func(char rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, char):
+			return '-'
+		case unicode.IsControl(char):
+			return ' '
+		}
+
+		return char
+	}, title)
+
+	if len(name) > outputdir.MaxNameLength {
+		name = name[:outputdir.MaxNameLength]
+	}
+	name = strings.TrimSpace(strings.TrimRight(strings.ToValidUTF8(name, ""), ". "))
+
+	if name == "" {
+		return "untitled"
+	}
+
+	// What is left invalid is a reserved name, such as CON.
+	if outputdir.ValidateName(name) != nil {
+		base, extension, _ := strings.Cut(name, ".")
+		name = strings.TrimSpace(base) + "_"
+		if extension != "" {
+			name += "." + extension
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,165 @@
+package naming
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/raphaeltannous/fem-helper/api"
+)
+
+func testCourse() api.CourseData {
+	return api.CourseData{
+		Slug:        "go-basics",
+		LessonsHash: map[int]string{0: "a", 1: "b"},
+		Sections: api.Sections{
+			{Title: "Intro", LessonsIndex: []int{0}},
+			{Title: "Types: Part 1", LessonsIndex: []int{1}},
+		},
+		Lessons: map[string]api.LessonData{
+			"a": {Slug: "welcome", Title: "Welcome", Index: 0},
+			"b": {Slug: "structs", Title: "Structs & Maps", Index: 1},
+		},
+	}
+}
+
+func TestScheme_Layout(t *testing.T) {
+	layoutTests := []struct {
+		name   string
+		scheme Scheme
+		want   Layout
+	}{
+		{
+			name:   "default",
+			scheme: DefaultScheme(),
+			want: Layout{
+				CourseFile:   "go-basics.md",
+				SectionDirs:  []string{"0-intro", "1-types-part-1"},
+				SectionSlugs: []string{"intro", "types-part-1"},
+				SectionFiles: []string{"0-intro/0-intro.md", "1-types-part-1/1-types-part-1.md"},
+				LessonFiles:  map[int]string{0: "0-intro/0-welcome.md", 1: "1-types-part-1/1-structs.md"},
+			},
+		},
+		{
+			name: "padded one-based",
+			scheme: Scheme{
+				SectionName: "{{pad .Index 2}} {{.Title}}",
+				LessonName:  "{{pad .Index}}-{{.Name}}",
+				OneBased:    true,
+				Padding:     3,
+			},
+			want: Layout{
+				CourseFile:   "go-basics.md",
+				SectionDirs:  []string{"01 Intro", "02 Types: Part 1"},
+				SectionSlugs: []string{"intro", "types-part-1"},
+				SectionFiles: []string{"01 Intro/01 Intro.md", "02 Types: Part 1/02 Types: Part 1.md"},
+				LessonFiles:  map[int]string{0: "01 Intro/001-welcome.md", 1: "02 Types: Part 1/002-structs.md"},
+			},
+		},
+		{
+			name:   "flat titles",
+			scheme: Scheme{Flat: true, TitleNames: true},
+			want: Layout{
+				CourseFile:   "go-basics.md",
+				SectionDirs:  []string{"", ""},
+				SectionSlugs: []string{"intro", "types-part-1"},
+				SectionFiles: []string{"0-Intro.md", "1-Types- Part 1.md"},
+				LessonFiles:  map[int]string{0: "0-Welcome.md", 1: "1-Structs & Maps.md"},
+			},
+		},
+	}
+
+	for _, c := range layoutTests {
+		t.Run(c.name, func(t *testing.T) {
+			answer, err := c.scheme.Layout(testCourse())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(answer, c.want) {
+				t.Errorf("got %+v, want %+v", answer, c.want)
+			}
+		})
+	}
+}
+
+func TestScheme_LayoutInvalidTemplate(t *testing.T) {
+	scheme := Scheme{SectionName: "{{.Missing}}"}
+
+	if _, err := scheme.Layout(testCourse()); err == nil {
+		t.Error("got nil error for an invalid template")
+	}
+}
+
+func TestScheme_LayoutDuplicateSlugs(t *testing.T) {
+	course := testCourse()
+	course.Sections[1].Title = "Intro!"
+	course.Lessons["b"] = api.LessonData{Slug: "welcome", Title: "Welcome Back", Index: 1}
+
+	scheme := DefaultScheme()
+	scheme.SectionName = "{{.Name}}"
+	scheme.LessonName = "{{.Name}}"
+
+	answer, err := scheme.Layout(course)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Layout{
+		CourseFile:   "go-basics.md",
+		SectionDirs:  []string{"intro", "intro-2"},
+		SectionSlugs: []string{"intro", "intro-2"},
+		SectionFiles: []string{"intro/intro.md", "intro-2/intro-2.md"},
+		LessonFiles:  map[int]string{0: "intro/welcome.md", 1: "intro-2/welcome-2.md"},
+	}
+	if !reflect.DeepEqual(answer, want) {
+		t.Errorf("got %+v, want %+v", answer, want)
+	}
+}
+
+func TestScheme_LayoutDuplicateFiles(t *testing.T) {
+	scheme := DefaultScheme()
+	scheme.Flat = true
+	scheme.LessonName = "{{pad .Index}}-{{.Title}}"
+	scheme.SectionName = "{{pad .Index}}-{{.Title}}"
+
+	course := testCourse()
+	course.Lessons["a"] = api.LessonData{Slug: "welcome", Title: "intro", Index: 0}
+
+	answer, err := scheme.Layout(course)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if answer.LessonFiles[0] != "0-intro.md" || answer.SectionFiles[0] != "0-Intro-2.md" {
+		t.Errorf("got lesson %s and section %s, want 0-intro.md and 0-Intro-2.md", answer.LessonFiles[0], answer.SectionFiles[0])
+	}
+}
+
+func TestLayout_Unique(t *testing.T) {
+	scheme := DefaultScheme()
+	scheme.Flat = true
+	scheme.LessonName = "go-basics-progress"
+
+	course := testCourse()
+	answer, err := scheme.Layout(course)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uniqueTests := []struct {
+		path string
+		want string
+	}{
+		{"go-basics.base", "go-basics.base"},
+		{"Go-Basics.md", "Go-Basics-2.md"},
+		{"go-basics-progress.md", "go-basics-progress-3.md"},
+	}
+
+	for _, c := range uniqueTests {
+		t.Run(c.path, func(t *testing.T) {
+			if got := answer.Unique(c.path); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raphaeltannous/fem-helper/outputdir"
+)
+
+func TestSanitizeTitle(t *testing.T) {
+	titleTests := []struct {
+		title string
+		want  string
+	}{
+		{"Types: Part 1", "Types- Part 1"},
+		{"Wrap up.", "Wrap up"},
+		{"What's next? ...", "What's next-"},
+		{"Con", "Con_"},
+		{"aux.config", "aux_.config"},
+		{"Line\tbreak\n", "Line break"},
+		{"...", "untitled"},
+		{strings.Repeat("é", 200), strings.Repeat("é", 127)},
+	}
+
+	for _, c := range titleTests {
+		t.Run(c.title, func(t *testing.T) {
+			answer := sanitizeTitle(c.title)
+
+			if answer != c.want {
+				t.Errorf("got %q, want %q", answer, c.want)
+			}
+
+			if err := outputdir.ValidateName(answer); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestScheme_LayoutLongTitles(t *testing.T) {
+	course := testCourse()
+	course.Sections[0].Title = strings.Repeat("Section ", 40)
+	lesson := course.Lessons["a"]
+	lesson.Title = strings.Repeat("é", 200)
+	course.Lessons["a"] = lesson
+
+	scheme := DefaultScheme()
+	scheme.TitleNames = true
+	scheme.Padding = 3
+
+	layout, err := scheme.Layout(course)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, notePath := range []string{layout.SectionFiles[0], layout.LessonFiles[0]} {
+		if err := outputdir.ValidatePath(notePath); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Titles are only shortened as much as needed, up to the trailing
+	// space that is trimmed.
+	sectionDir := layout.SectionDirs[0]
+	if want := outputdir.MaxNameLength - len(".md"); len(sectionDir) < want-1 || len(sectionDir) > want {
+		t.Errorf("got %d bytes, want %d", len(sectionDir), want)
+	}
+}
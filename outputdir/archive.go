@@ -0,0 +1,215 @@
+package outputdir
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+type archiveEntry struct {
+	name string
+	data []byte
+	dir  bool
+}
+
+// archiveFS buffers every write in memory, and writes them as a single
+// archive to path on Commit.
+type archiveFS struct {
+	path    string
+	options Options
+	entries []archiveEntry
+	index   map[string]int
+	write   func(io.Writer, []archiveEntry, Options) error
+	done    bool
+}
+
+// Returns an FS that writes a zip archive to path on Commit.
+func NewZip(path string, options Options) (FS, error) {
+	return newArchiveFS(path, options, writeZip)
+}
+
+// Returns an FS that writes a gzip compressed tar archive to path on Commit.
+func NewTarGz(path string, options Options) (FS, error) {
+	return newArchiveFS(path, options, writeTarGz)
+}
+
+func newArchiveFS(path string, options Options, write func(io.Writer, []archiveEntry, Options) error) (*archiveFS, error) {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveFS{
+		path:    absolutePath,
+		options: options,
+		index:   make(map[string]int),
+		write:   write,
+	}, nil
+}
+
+func (archive *archiveFS) MkdirAll(name string) error {
+	if archive.done {
+		return ErrTransactionDone
+	}
+
+	if err := ValidatePath(name); err != nil {
+		return err
+	}
+
+	name = memPath(name)
+	if name == "." {
+		return nil
+	}
+
+	archive.MkdirAll(path.Dir(name))
+	archive.add(archiveEntry{name: name + "/", dir: true})
+
+	return nil
+}
+
+func (archive *archiveFS) WriteFile(name string, data []byte) error {
+	if archive.done {
+		return ErrTransactionDone
+	}
+
+	if err := ValidatePath(name); err != nil {
+		return err
+	}
+
+	name = memPath(name)
+	archive.MkdirAll(path.Dir(name))
+	archive.add(archiveEntry{name: name, data: data})
+
+	return nil
+}
+
+// Adds entry to the archive, replacing any entry with the same name.
+func (archive *archiveFS) add(entry archiveEntry) {
+	if i, ok := archive.index[entry.name]; ok {
+		archive.entries[i] = entry
+		return
+	}
+
+	archive.index[entry.name] = len(archive.entries)
+	archive.entries = append(archive.entries, entry)
+}
+
+// Writes the archive next to its path first, and renames it into place
+// once it was completely written.
+func (archive *archiveFS) Commit() error {
+	if archive.done {
+		return ErrTransactionDone
+	}
+	archive.done = true
+
+	tmpFile, err := os.CreateTemp(
+		filepath.Dir(archive.path),
+		"."+filepath.Base(archive.path)+"-staging-",
+	)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if err := archive.write(tmpFile, archive.entries, archive.options); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Chmod(archive.fileMode()); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), archive.path)
+}
+
+// Returns the mode of the existing archive if there is one, otherwise the
+// configured file mode.
+func (archive *archiveFS) fileMode() os.FileMode {
+	if info, err := os.Stat(archive.path); err == nil && info.Mode().IsRegular() {
+		return info.Mode().Perm()
+	}
+
+	return archive.options.FileMode
+}
+
+func (archive *archiveFS) Rollback() error {
+	archive.done = true
+	archive.entries = nil
+	clear(archive.index)
+
+	return nil
+}
+
+func writeZip(w io.Writer, entries []archiveEntry, options Options) error {
+	zipWriter := zip.NewWriter(w)
+	modified := time.Now()
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:     entry.name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		}
+		header.SetMode(options.FileMode)
+		if entry.dir {
+			header.Method = zip.Store
+			header.SetMode(os.ModeDir | options.DirMode)
+		}
+
+		file, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if _, err := file.Write(entry.data); err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+func writeTarGz(w io.Writer, entries []archiveEntry, options Options) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	modified := time.Now()
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: tar.TypeReg,
+			Mode:     int64(options.FileMode.Perm()),
+			Size:     int64(len(entry.data)),
+			ModTime:  modified,
+		}
+		if entry.dir {
+			header.Typeflag = tar.TypeDir
+			header.Mode = int64(options.DirMode.Perm())
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tarWriter.Write(entry.data); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
@@ -0,0 +1,104 @@
+package outputdir
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveFS_Zip(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "course.zip")
+
+	archive, err := Open(archivePath, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive.WriteFile("course.md", []byte("course"))
+	archive.WriteFile("0-intro/0-welcome.md", []byte("welcome"))
+	if err := archive.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	zipReader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zipReader.Close()
+
+	var names []string
+	for _, file := range zipReader.File {
+		names = append(names, file.Name)
+	}
+
+	want := []string{"course.md", "0-intro/", "0-intro/0-welcome.md"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestArchiveFS_TarGz(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "course.tar.gz")
+
+	archive, err := Open(archivePath, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive.WriteFile("0-intro/0-welcome.md", []byte("welcome"))
+	if err := archive.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	contents := make(map[string]string)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, _ := io.ReadAll(tarReader)
+		contents[header.Name] = string(data)
+	}
+
+	want := map[string]string{"0-intro/": "", "0-intro/0-welcome.md": "welcome"}
+	if !reflect.DeepEqual(contents, want) {
+		t.Errorf("got %v, want %v", contents, want)
+	}
+}
+
+func TestArchiveFS_Rollback(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "course.zip")
+
+	archive, err := Open(archivePath, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive.WriteFile("course.md", []byte("course"))
+	archive.Rollback()
+
+	if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+		t.Errorf("archive %s was written after rollback", archivePath)
+	}
+}
@@ -0,0 +1,41 @@
+package outputdir
+
+import (
+	"strings"
+)
+
+// FS is a writable file system that the generated notes are written to.
+// Writes are only guaranteed to be visible once Commit returns, and
+// Rollback discards every write that was not committed yet.
+//
+// Names are slash or OS separated paths relative to the root of the FS.
+type FS interface {
+	MkdirAll(name string) error
+	WriteFile(name string, data []byte) error
+	Commit() error
+	Rollback() error
+}
+
+var (
+	_ FS = (*Transaction)(nil)
+	_ FS = (*MemFS)(nil)
+	_ FS = (*archiveFS)(nil)
+)
+
+// Opens the FS that path refers to. Paths ending with .zip, .tar.gz or
+// .tgz are written as archives, anything else is a directory on disk.
+func Open(path string, options Options) (FS, error) {
+	switch {
+	case strings.HasSuffix(path, ".zip"):
+		return NewZip(path, options)
+	case strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".tgz"):
+		return NewTarGz(path, options)
+	}
+
+	dir, err := NewOutputDirectory(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir.Begin(options)
+}
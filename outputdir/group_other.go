@@ -0,0 +1,11 @@
+//go:build !unix
+
+package outputdir
+
+import "os"
+
+// Returns the group id of the file info describes. Files have no group id
+// on this platform.
+func fileGroup(info os.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package outputdir
+
+import (
+	"os"
+	"syscall"
+)
+
+// Returns the group id of the file info describes.
+func fileGroup(info os.FileInfo) (int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return int(stat.Gid), true
+}
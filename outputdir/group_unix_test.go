@@ -0,0 +1,78 @@
+//go:build unix
+
+package outputdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransaction_Group(t *testing.T) {
+	const (
+		dirGID  = 12345
+		fileGID = 23456
+	)
+
+	dir, err := NewOutputDirectory(filepath.Join(t.TempDir(), "vault"), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := filepath.Join(dir.String(), "course.md")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chown(dir.String(), -1, dirGID); err != nil {
+		t.Skipf("can not change the group: %v", err)
+	}
+	if err := os.Chown(existing, -1, fileGID); err != nil {
+		t.Skipf("can not change the group: %v", err)
+	}
+	if err := os.Chmod(dir.String(), 0755|os.ModeSetgid); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := dir.Begin(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx.WriteFile("course.md", []byte("new"))
+	tx.WriteFile("0-intro/0-welcome.md", []byte("welcome"))
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	groupTests := []struct {
+		name   string
+		want   int
+		setgid bool
+	}{
+		{"course.md", fileGID, false},
+		{"0-intro", dirGID, true},
+		{"0-intro/0-welcome.md", dirGID, false},
+	}
+
+	for _, c := range groupTests {
+		t.Run(c.name, func(t *testing.T) {
+			info, err := os.Stat(filepath.Join(dir.String(), c.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if gid, _ := fileGroup(info); gid != c.want {
+				t.Errorf("got group %d, want %d", gid, c.want)
+			}
+
+			if setgid := info.Mode()&os.ModeSetgid != 0; setgid != c.setgid {
+				t.Errorf("got setgid %v, want %v", setgid, c.setgid)
+			}
+
+			if info.IsDir() && info.Mode().Perm() != 0755 {
+				t.Errorf("got %#o, want %#o", info.Mode().Perm(), 0755)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package outputdir
+
+import (
+	"io/fs"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// MemFS is an in-memory FS, mostly useful in tests.
+type MemFS struct {
+	committed map[string][]byte
+	staged    map[string][]byte
+}
+
+func NewMemFS() *MemFS {
+	return &MemFS{
+		committed: make(map[string][]byte),
+		staged:    make(map[string][]byte),
+	}
+}
+
+// Directories are implicit in MemFS.
+func (memFS *MemFS) MkdirAll(name string) error {
+	return ValidatePath(name)
+}
+
+func (memFS *MemFS) WriteFile(name string, data []byte) error {
+	if err := ValidatePath(name); err != nil {
+		return err
+	}
+
+	memFS.staged[memPath(name)] = slices.Clone(data)
+	return nil
+}
+
+func (memFS *MemFS) Commit() error {
+	maps.Copy(memFS.committed, memFS.staged)
+	clear(memFS.staged)
+	return nil
+}
+
+func (memFS *MemFS) Rollback() error {
+	clear(memFS.staged)
+	return nil
+}
+
+// Returns the committed content of name.
+func (memFS *MemFS) ReadFile(name string) ([]byte, error) {
+	data, ok := memFS.committed[memPath(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return slices.Clone(data), nil
+}
+
+// Returns the sorted names of all committed files.
+func (memFS *MemFS) Names() []string {
+	return slices.Sorted(maps.Keys(memFS.committed))
+}
+
+// Returns name as a clean slash separated path.
+func memPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
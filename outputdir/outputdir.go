@@ -7,25 +7,40 @@ import (
 
 type OutputDirectory string
 
-func NewOutputDirectory(path string) (OutputDirectory, error) {
+// Options configures how files and directories are written.
+type Options struct {
+	// Mode of new files. Files that already exist keep their mode.
+	FileMode os.FileMode
+	// Mode of new directories. Directories that already exist keep their mode.
+	DirMode os.FileMode
+}
+
+func DefaultOptions() Options {
+	return Options{
+		FileMode: 0644,
+		DirMode:  0755,
+	}
+}
+
+func NewOutputDirectory(path string, options Options) (OutputDirectory, error) {
 	absolutePath, err := filepath.Abs(path)
 	if err != nil {
 		return "", err
 	}
 
 	dir := OutputDirectory(absolutePath)
-	return dir.Create("")
+	return dir.Create("", options.DirMode)
 }
 
-// Creates the relativePath to OutputDirectory in it. Returns a new OutputDirectory of the relative type.
+// Creates the relativePath to OutputDirectory in it with mode. Returns a new OutputDirectory of the relative type.
 // You can pass "" to relativePath to Create for OutputDirectory.
-func (dir OutputDirectory) Create(relativePath string) (OutputDirectory, error) {
+func (dir OutputDirectory) Create(relativePath string, mode os.FileMode) (OutputDirectory, error) {
+	joinedPath := dir.relativeToAbsolute(relativePath)
 	if dir.isPresent(relativePath) {
-		return OutputDirectory(dir.relativeToAbsolute(relativePath)), nil
+		return OutputDirectory(joinedPath), nil
 	}
 
-	joinedPath := dir.relativeToAbsolute(relativePath)
-	err := os.MkdirAll(joinedPath, 0750)
+	_, err := mkdirAllTracked(joinedPath, mode)
 
 	return OutputDirectory(joinedPath), err
 }
@@ -49,15 +64,6 @@ func (dir OutputDirectory) relativeToAbsolute(relativePath string) string {
 	return joinedPath
 }
 
-// Writes data to a file in OutputDirectory.
-func (dir OutputDirectory) writeFile(filename string, data string) error {
-	return os.WriteFile(
-		dir.relativeToAbsolute(filename),
-		[]byte(data),
-		0700,
-	)
-}
-
 func (dir OutputDirectory) String() string {
 	return string(dir)
 }
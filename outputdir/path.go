@@ -0,0 +1,120 @@
+package outputdir
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Maximum length in bytes of a single path component.
+const MaxNameLength = 255
+
+// Names that can not be used as file names on Windows, with or without an
+// extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// InvalidPathError is returned for paths that can not be safely written
+// inside the output root.
+type InvalidPathError struct {
+	Path   string
+	Reason string
+}
+
+func (err *InvalidPathError) Error() string {
+	return fmt.Sprintf("invalid output path %q: %s", err.Path, err.Reason)
+}
+
+// Checks that name is a relative path that stays inside the output root,
+// and that each of its components is a portable file name. Computed paths
+// never need "..", so any ".." component is rejected.
+func ValidatePath(name string) error {
+	slashed := filepath.ToSlash(name)
+
+	switch {
+	case strings.TrimSpace(name) == "":
+		return &InvalidPathError{name, "empty path"}
+	case path.IsAbs(slashed), filepath.IsAbs(name), filepath.VolumeName(name) != "":
+		return &InvalidPathError{name, "absolute path"}
+	}
+
+	if slashed == "." {
+		return nil
+	}
+
+	for component := range strings.SplitSeq(slashed, "/") {
+		if component == ".." {
+			return &InvalidPathError{name, "escapes the output root"}
+		}
+
+		if err := ValidateName(component); err != nil {
+			return &InvalidPathError{name, err.(*InvalidPathError).Reason}
+		}
+	}
+
+	return nil
+}
+
+// Checks that name is a single portable file name.
+func ValidateName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return &InvalidPathError{name, "invalid file name"}
+	}
+
+	if len(name) > MaxNameLength {
+		return &InvalidPathError{name, fmt.Sprintf("file name longer than %d bytes", MaxNameLength)}
+	}
+
+	for _, char := range name {
+		if unicode.IsControl(char) {
+			return &InvalidPathError{name, "control character in file name"}
+		}
+
+		if strings.ContainsRune(`<>:"/\|?*`, char) {
+			return &InvalidPathError{name, fmt.Sprintf("character %q not allowed in file names", char)}
+		}
+	}
+
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return &InvalidPathError{name, "file name ends with a dot or a space"}
+	}
+
+	baseName, _, _ := strings.Cut(name, ".")
+	if reservedNames[strings.ToUpper(strings.TrimSpace(baseName))] {
+		return &InvalidPathError{name, "reserved file name"}
+	}
+
+	return nil
+}
+
+// Checks that no existing component of relativePath inside the
+// OutputDirectory is a symlink, so writes can not be redirected outside it.
+func (dir OutputDirectory) checkSymlinks(relativePath string) error {
+	current := dir.String()
+
+	for component := range strings.SplitSeq(filepath.Clean(relativePath), string(filepath.Separator)) {
+		current = filepath.Join(current, component)
+
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return &InvalidPathError{relativePath, "symlink inside the output directory"}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package outputdir
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidatePath(t *testing.T) {
+	pathTests := []struct {
+		path  string
+		valid bool
+	}{
+		{"go-basics.md", true},
+		{"0-intro/0-welcome.md", true},
+		{"0-intro/../1-setup/1-install.md", false},
+		{"0-intro//1-install.md", false},
+		{"Café & Crème/0-Déjà vu.md", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../outside.md", false},
+		{"0-intro/../../outside.md", false},
+		{"0-intro/1-a:b.md", false},
+		{"0-intro/1-a\\b.md", false},
+		{"0-intro/1-bell\a.md", false},
+		{"0-intro/trailing.", false},
+		{"0-intro/trailing /1.md", false},
+		{"con/1.md", false},
+		{"0-intro/LPT1.md", false},
+		{"0-intro/" + strings.Repeat("a", 256), false},
+	}
+
+	for _, c := range pathTests {
+		t.Run(c.path, func(t *testing.T) {
+			err := ValidatePath(c.path)
+
+			if c.valid && err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+
+			var invalidPath *InvalidPathError
+			if !c.valid && !errors.As(err, &invalidPath) {
+				t.Errorf("got %v, want an InvalidPathError", err)
+			}
+		})
+	}
+}
+
+func TestTransaction_Symlink(t *testing.T) {
+	outside := t.TempDir()
+
+	dir, err := NewOutputDirectory(filepath.Join(t.TempDir(), "vault"), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(outside, filepath.Join(dir.String(), "0-intro")); err != nil {
+		t.Skip(err)
+	}
+
+	tx, err := dir.Begin(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var invalidPath *InvalidPathError
+	if err := tx.WriteFile("0-intro/0-welcome.md", []byte("welcome")); !errors.As(err, &invalidPath) {
+		t.Errorf("got %v, want an InvalidPathError", err)
+	}
+}
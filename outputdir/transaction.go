@@ -0,0 +1,352 @@
+package outputdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrTransactionDone = errors.New("transaction already committed or rolled back")
+
+// Transaction stages every write to an OutputDirectory in a temporary
+// directory next to it, and moves the staged files into place on Commit.
+// The OutputDirectory is left untouched until Commit, which moves the files
+// one by one and moves the replaced files back if a move fails.
+//
+// Commit is only atomic per file: a crash during Commit leaves a mix of
+// old and new files, with the replaced files kept in the .backup folder of
+// the staging directory.
+type Transaction struct {
+	dir     OutputDirectory
+	options Options
+	staging string
+	files   []string
+	dirs    []string
+	done    bool
+}
+
+// Staging directories older than this are left over by a crash, and are
+// removed by Begin.
+const staleStagingAge = time.Hour
+
+// Begins a new Transaction on OutputDirectory. The staging directory is
+// created alongside the OutputDirectory so that commits are plain renames
+// on the same filesystem.
+func (dir OutputDirectory) Begin(options Options) (*Transaction, error) {
+	dir.removeStaleStaging()
+
+	staging, err := os.MkdirTemp(filepath.Dir(dir.String()), dir.stagingPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		dir:     dir,
+		options: options,
+		staging: staging,
+	}, nil
+}
+
+// Creates relativePath in the staging directory. The directory is created
+// in the OutputDirectory on Commit, even if no file is written in it.
+func (tx *Transaction) MkdirAll(relativePath string) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+
+	if err := tx.validate(relativePath); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(tx.stagingPath(relativePath), 0750); err != nil {
+		return err
+	}
+
+	cleanPath := filepath.Clean(relativePath)
+	if !slices.Contains(tx.dirs, cleanPath) {
+		tx.dirs = append(tx.dirs, cleanPath)
+	}
+
+	return nil
+}
+
+// Writes data to relativePath in the staging directory.
+func (tx *Transaction) WriteFile(relativePath string, data []byte) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+
+	if err := tx.validate(relativePath); err != nil {
+		return err
+	}
+
+	stagedPath := tx.stagingPath(relativePath)
+	if err := os.MkdirAll(filepath.Dir(stagedPath), 0750); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(stagedPath, data, 0600); err != nil {
+		return err
+	}
+
+	// The staged file is renamed into place, so it needs its final mode.
+	if err := os.Chmod(stagedPath, tx.fileMode(relativePath)); err != nil {
+		return err
+	}
+
+	cleanPath := filepath.Clean(relativePath)
+	if !slices.Contains(tx.files, cleanPath) {
+		tx.files = append(tx.files, cleanPath)
+	}
+
+	return nil
+}
+
+// Creates every staged directory and moves every staged file into the
+// OutputDirectory. Files that are replaced are backed up first, and if any
+// move fails all the previous moves are undone before returning the error.
+// If undoing fails too, the staging directory, which holds the backups, is
+// kept and its path is part of the error.
+func (tx *Transaction) Commit() error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	tx.done = true
+
+	backupDir := filepath.Join(tx.staging, ".backup")
+
+	var (
+		committed   []string
+		backedUp    []string
+		createdDirs []string
+	)
+
+	undo := func() error {
+		var errs []error
+
+		for _, relativePath := range slices.Backward(committed) {
+			if err := os.Remove(tx.dir.relativeToAbsolute(relativePath)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		for _, relativePath := range slices.Backward(backedUp) {
+			if err := os.Rename(
+				filepath.Join(backupDir, relativePath),
+				tx.dir.relativeToAbsolute(relativePath),
+			); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		// Directories that are not empty, such as the ones a restore
+		// failed in, are kept.
+		for _, createdDir := range slices.Backward(createdDirs) {
+			os.Remove(createdDir)
+		}
+
+		return errors.Join(errs...)
+	}
+
+	fail := func(relativePath string, err error) error {
+		err = fmt.Errorf("commit %s: %w", relativePath, err)
+
+		if undoErr := undo(); undoErr != nil {
+			return fmt.Errorf("%w; undoing the commit failed, the previous files are kept in %s: %w", err, backupDir, undoErr)
+		}
+
+		os.RemoveAll(tx.staging)
+		return err
+	}
+
+	for _, relativePath := range tx.dirs {
+		if err := tx.dir.checkSymlinks(relativePath); err != nil {
+			return fail(relativePath, err)
+		}
+
+		dirs, err := mkdirAllTracked(tx.dir.relativeToAbsolute(relativePath), tx.options.DirMode)
+		createdDirs = append(createdDirs, dirs...)
+		if err != nil {
+			return fail(relativePath, err)
+		}
+	}
+
+	for _, relativePath := range tx.files {
+		target := tx.dir.relativeToAbsolute(relativePath)
+
+		if err := tx.dir.checkSymlinks(relativePath); err != nil {
+			return fail(relativePath, err)
+		}
+
+		dirs, err := mkdirAllTracked(filepath.Dir(target), tx.options.DirMode)
+		createdDirs = append(createdDirs, dirs...)
+		if err != nil {
+			return fail(relativePath, err)
+		}
+
+		if err := setFileGroup(tx.stagingPath(relativePath), target); err != nil {
+			return fail(relativePath, err)
+		}
+
+		if _, err := os.Lstat(target); err == nil {
+			backup := filepath.Join(backupDir, relativePath)
+			if err := os.MkdirAll(filepath.Dir(backup), 0700); err != nil {
+				return fail(relativePath, err)
+			}
+
+			if err := os.Rename(target, backup); err != nil {
+				return fail(relativePath, err)
+			}
+			backedUp = append(backedUp, relativePath)
+		}
+
+		if err := os.Rename(tx.stagingPath(relativePath), target); err != nil {
+			return fail(relativePath, err)
+		}
+		committed = append(committed, relativePath)
+	}
+
+	os.RemoveAll(tx.staging)
+	return nil
+}
+
+// Returns the name prefix of the staging directories of OutputDirectory.
+func (dir OutputDirectory) stagingPrefix() string {
+	return "." + filepath.Base(dir.String()) + "-staging-"
+}
+
+// Removes the staging directories that earlier transactions left behind
+// when they crashed. Staging directories holding the backups of a commit
+// that could not be undone are kept, as are recent ones, which may belong
+// to a transaction that is still running.
+func (dir OutputDirectory) removeStaleStaging() {
+	parent := filepath.Dir(dir.String())
+
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), dir.stagingPrefix()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < staleStagingAge {
+			continue
+		}
+
+		staging := filepath.Join(parent, entry.Name())
+		if _, err := os.Lstat(filepath.Join(staging, ".backup")); err == nil {
+			continue
+		}
+
+		os.RemoveAll(staging)
+	}
+}
+
+// Discards every staged write. Calling Rollback after Commit is a no-op,
+// which makes it safe to defer.
+func (tx *Transaction) Rollback() error {
+	if tx.done {
+		return nil
+	}
+	tx.done = true
+
+	return os.RemoveAll(tx.staging)
+}
+
+// Checks that relativePath can be safely written to the OutputDirectory.
+func (tx *Transaction) validate(relativePath string) error {
+	if err := ValidatePath(relativePath); err != nil {
+		return err
+	}
+
+	return tx.dir.checkSymlinks(relativePath)
+}
+
+// Returns the mode relativePath is written with: the mode of the existing
+// file if there is one, otherwise the configured file mode.
+func (tx *Transaction) fileMode(relativePath string) os.FileMode {
+	if info, err := os.Stat(tx.dir.relativeToAbsolute(relativePath)); err == nil && info.Mode().IsRegular() {
+		return info.Mode().Perm()
+	}
+
+	return tx.options.FileMode
+}
+
+// Returns the path of relativePath inside the staging directory.
+func (tx *Transaction) stagingPath(relativePath string) string {
+	return filepath.Join(tx.staging, relativePath)
+}
+
+// Gives the staged file the group a file written in place at target would
+// have: the group of the file it replaces, or the group of the directory
+// if the directory has the setgid bit. Groups the user is not a member of
+// can not be set, in which case the file keeps its group.
+func setFileGroup(staged, target string) error {
+	info, err := os.Lstat(target)
+	if err != nil {
+		info, err = os.Stat(filepath.Dir(target))
+		if err != nil || info.Mode()&os.ModeSetgid == 0 {
+			return nil
+		}
+	}
+
+	gid, ok := fileGroup(info)
+	if !ok {
+		return nil
+	}
+
+	if err := os.Lchown(staged, -1, gid); err != nil && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+
+	return nil
+}
+
+// Same as os.MkdirAll, but returns the directories that were created,
+// deepest last, so they can be removed again. The created directories get
+// exactly perm, regardless of the umask, plus the setgid bit they inherit
+// from their parent.
+func mkdirAllTracked(path string, perm os.FileMode) ([]string, error) {
+	var missing []string
+
+	for current := path; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(current); err == nil {
+			break
+		}
+
+		missing = append(missing, current)
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	var created []string
+	for _, dir := range slices.Backward(missing) {
+		if err := os.Mkdir(dir, perm); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				continue
+			}
+			return created, err
+		}
+		created = append(created, dir)
+
+		info, err := os.Stat(dir)
+		if err != nil {
+			return created, err
+		}
+
+		if err := os.Chmod(dir, perm|info.Mode()&os.ModeSetgid); err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
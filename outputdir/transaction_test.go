@@ -0,0 +1,245 @@
+package outputdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	dir, err := NewOutputDirectory(filepath.Join(t.TempDir(), "vault"), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir.String(), "course.md"), []byte("old"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := dir.Begin(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"course.md":            "new",
+		"0-intro/0-welcome.md": "welcome",
+	}
+	for name, data := range files {
+		if err := tx.WriteFile(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tx.MkdirAll("1-empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir.String(), "course.md")); string(data) != "old" {
+		t.Errorf("course.md changed before commit: got %q", data)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dir.String(), "1-empty")); err != nil || !info.IsDir() {
+		t.Errorf("staged directory 1-empty was not created: %v", err)
+	}
+
+	for name, want := range files {
+		data, err := os.ReadFile(filepath.Join(dir.String(), name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != want {
+			t.Errorf("%s: got %q, want %q", name, data, want)
+		}
+	}
+
+	if _, err := os.Stat(tx.staging); !os.IsNotExist(err) {
+		t.Errorf("staging directory %s was not removed", tx.staging)
+	}
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	dir, err := NewOutputDirectory(filepath.Join(t.TempDir(), "vault"), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := dir.Begin(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.WriteFile("0-intro/0-welcome.md", []byte("welcome")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Errorf("got %d entries after rollback, want 0", len(entries))
+	}
+
+	if err := tx.Commit(); err != ErrTransactionDone {
+		t.Errorf("got %v, want %v", err, ErrTransactionDone)
+	}
+}
+
+func TestTransaction_Modes(t *testing.T) {
+	options := Options{FileMode: 0640, DirMode: 0710}
+
+	dir, err := NewOutputDirectory(filepath.Join(t.TempDir(), "vault"), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := filepath.Join(dir.String(), "course.md")
+	if err := os.WriteFile(existing, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(existing, 0660); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := dir.Begin(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx.WriteFile("course.md", []byte("new"))
+	tx.WriteFile("0-intro/0-welcome.md", []byte("welcome"))
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	modeTests := []struct {
+		name string
+		want os.FileMode
+	}{
+		{"", 0710},
+		{"course.md", 0660},
+		{"0-intro", 0710},
+		{"0-intro/0-welcome.md", 0640},
+	}
+
+	for _, c := range modeTests {
+		t.Run(c.name, func(t *testing.T) {
+			info, err := os.Stat(filepath.Join(dir.String(), c.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.Mode().Perm() != c.want {
+				t.Errorf("got %#o, want %#o", info.Mode().Perm(), c.want)
+			}
+		})
+	}
+}
+
+func TestTransaction_CommitUndo(t *testing.T) {
+	root := t.TempDir()
+	dir, err := NewOutputDirectory(filepath.Join(root, "vault"), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir.String(), "course.md"), []byte("old"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := dir.Begin(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"course.md", "new.md", "0-intro/0-welcome.md"} {
+		if err := tx.WriteFile(name, []byte("new")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The section folder is replaced by a symlink after staging, so the
+	// last file can not be committed.
+	if err := os.Symlink(root, filepath.Join(dir.String(), "0-intro")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err == nil {
+		t.Fatal("got no error committing through a symlink")
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir.String(), "course.md")); string(data) != "old" {
+		t.Errorf("course.md was not restored: got %q", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir.String(), "new.md")); !os.IsNotExist(err) {
+		t.Error("new.md was not removed")
+	}
+
+	if _, err := os.Stat(tx.staging); !os.IsNotExist(err) {
+		t.Errorf("staging directory %s was not removed", tx.staging)
+	}
+}
+
+func TestOutputDirectory_BeginRemovesStaleStaging(t *testing.T) {
+	dir, err := NewOutputDirectory(filepath.Join(t.TempDir(), "vault"), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := filepath.Dir(dir.String())
+
+	old := time.Now().Add(-2 * staleStagingAge)
+	stagingTests := []struct {
+		name    string
+		backup  bool
+		modTime time.Time
+		kept    bool
+	}{
+		{".vault-staging-crashed", false, old, false},
+		{".vault-staging-running", false, time.Now(), true},
+		{".vault-staging-undo-failed", true, old, true},
+		{".notes-staging-other", false, old, true},
+	}
+
+	for _, c := range stagingTests {
+		staging := filepath.Join(parent, c.name)
+		if err := os.MkdirAll(staging, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if c.backup {
+			if err := os.Mkdir(filepath.Join(staging, ".backup"), 0700); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Chtimes(staging, c.modTime, c.modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx, err := dir.Begin(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	for _, c := range stagingTests {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := os.Stat(filepath.Join(parent, c.name))
+			if kept := err == nil; kept != c.kept {
+				t.Errorf("got kept %v, want %v", kept, c.kept)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/raphaeltannous/fem-helper/cache"
+	"github.com/raphaeltannous/fem-helper/config"
+)
+
+// configFlag is the generate flag a config key sets.
+type configFlag struct {
+	// Name of the flag, followed by its shorthand if any.
+	names []string
+	// Set the flag once per item of a list, for repeatable flags.
+	repeat bool
+}
+
+// Generate flags by config key. Keys without a flag, such as the cache
+// settings, are applied by loadConfig.
+var configFlags = map[string]configFlag{
+	"output_dir":          {names: []string{"output-dir", "o"}},
+	"tags":                {names: []string{"tags", "t"}},
+	"tag_prefix":          {names: []string{"tag-prefix"}},
+	"flavor":              {names: []string{"flavor"}},
+	"templates":           {names: []string{"custom-template"}, repeat: true},
+	"naming.section_name": {names: []string{"section-name"}},
+	"naming.lesson_name":  {names: []string{"lesson-name"}},
+	"naming.one_based":    {names: []string{"one-based"}},
+	"naming.pad":          {names: []string{"pad"}},
+	"naming.flat":         {names: []string{"flat"}},
+	"naming.title_names":  {names: []string{"title-names"}},
+}
+
+// Adds the -profile flag to flagSet.
+func profileFlag(flagSet *flag.FlagSet) *string {
+	return flagSet.String("profile", "", fmt.Sprintf("Profile of the config to use, instead of %sPROFILE or the profile key of the config.", config.EnvPrefix))
+}
+
+// Returns the config of the config files and the environment, with
+// profile applied if not empty, and configures the cache with it.
+func loadConfig(profile string) (config.Config, error) {
+	loaded, err := config.Load(config.Options{
+		Files:   config.DefaultFiles(),
+		Profile: profile,
+		Environ: os.Environ(),
+	})
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	var cacheOptions cache.Options
+	if dir, ok := loaded.Values["cache.dir"]; ok {
+		cacheOptions.Dir = dir.Value.(string)
+	}
+	if enabled, ok := loaded.Values["cache.enabled"]; ok {
+		cacheOptions.Disabled = !enabled.Value.(bool)
+	}
+	cache.Configure(cacheOptions)
+
+	return loaded, nil
+}
+
+// Sets the flags of flagSet that were not given on the command line to
+// the values of loaded.
+func applyConfig(flagSet *flag.FlagSet, loaded config.Config) error {
+	givenFlags := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) {
+		givenFlags[f.Name] = true
+	})
+
+	for _, key := range config.Keys {
+		value, ok := loaded.Values[key.Name]
+		configFlag, hasFlag := configFlags[key.Name]
+		if !ok || !hasFlag || slices.ContainsFunc(configFlag.names, func(name string) bool { return givenFlags[name] }) {
+			continue
+		}
+
+		// An empty list leaves the flag as it is without config.
+		list, isList := value.Value.([]string)
+		if isList && len(list) == 0 {
+			continue
+		}
+
+		texts := []string{value.Text()}
+		if configFlag.repeat {
+			texts = list
+		}
+
+		for _, text := range texts {
+			if err := flagSet.Set(configFlag.names[0], text); err != nil {
+				return fmt.Errorf("%s (%s): %w", key.Name, value.Source, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the value key has without config.
+func defaultConfigValue(key config.Key) config.Value {
+	value := config.Value{Source: "default"}
+
+	switch key.Name {
+	case "cache.dir":
+		dir, _ := cache.DefaultDir()
+		value.Value = dir
+		return value
+	case "cache.enabled":
+		value.Value = true
+		return value
+	}
+
+	text := generateFlags.Lookup(configFlags[key.Name].names[0]).DefValue
+	switch key.Kind {
+	case config.Bool:
+		value.Value = text == "true"
+	case config.Int:
+		value.Value, _ = strconv.ParseInt(text, 10, 64)
+	case config.List:
+		value.Value = []string{}
+	default:
+		value.Value = text
+	}
+
+	return value
+}
+
+// Shows the effective configuration.
+func configCommand(args []string) error {
+	flagSet := newFlagSet("config", "show [-profile name]")
+	profile := profileFlag(flagSet)
+
+	if len(args) > 0 && args[0] == "show" {
+		args = args[1:]
+	} else if !slices.Contains(args, "-h") && !slices.Contains(args, "-help") {
+		flagSet.Usage()
+		os.Exit(2)
+	}
+	parseArgs(flagSet, args, 0)
+
+	loaded, err := loadConfig(*profile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# Files: %s\n", orNone(strings.Join(loaded.Files, ", ")))
+	if loaded.Profile != "" {
+		fmt.Printf("# Profile: %s (%s)\n", loaded.Profile, loaded.ProfileSource)
+	}
+	fmt.Println()
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for _, key := range config.Keys {
+		value, ok := loaded.Values[key.Name]
+		if !ok {
+			value = defaultConfigValue(key)
+		}
+
+		fmt.Fprintf(writer, "%s = %s\t# %s\n", key.Name, value.TOML(), value.Source)
+	}
+
+	return writer.Flush()
+}
+
+// Returns text, or "none" if it is empty.
+func orNone(text string) string {
+	if text == "" {
+		return "none"
+	}
+
+	return text
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raphaeltannous/fem-helper/config"
+	"github.com/raphaeltannous/fem-helper/templater"
+)
+
+// settingsFlags holds the flags of a test flag set, named like the
+// generate flags that config keys set.
+type settingsFlags struct {
+	outputDir string
+	tags      tags
+	templates customUserTemplates
+	oneBased  bool
+	pad       int
+}
+
+func newSettingsFlagSet() (*flag.FlagSet, *settingsFlags) {
+	flags := &settingsFlags{}
+	flagSet := flag.NewFlagSet("generate", flag.ContinueOnError)
+
+	flagSet.StringVar(&flags.outputDir, "output-dir", "", "")
+	flagSet.StringVar(&flags.outputDir, "o", "", "")
+	flagSet.Var(&flags.tags, "tags", "")
+	flagSet.Var(&flags.tags, "t", "")
+	flagSet.Var(&flags.templates, "custom-template", "")
+	flagSet.BoolVar(&flags.oneBased, "one-based", false, "")
+	flagSet.IntVar(&flags.pad, "pad", 0, "")
+
+	return flagSet, flags
+}
+
+func TestApplyConfig(t *testing.T) {
+	configTests := []struct {
+		name   string
+		args   []string
+		values map[string]any
+		want   settingsFlags
+	}{
+		{
+			name:   "list",
+			values: map[string]any{"tags": []string{"go", "web"}},
+			want:   settingsFlags{tags: tags{"go", "web"}},
+		},
+		{
+			name:   "empty list",
+			values: map[string]any{"tags": []string{}},
+			want:   settingsFlags{},
+		},
+		{
+			name:   "unset list",
+			values: map[string]any{"tags": []string(nil)},
+			want:   settingsFlags{},
+		},
+		{
+			name:   "repeated list",
+			values: map[string]any{"templates": []string{"a/course.tmpl", "b/lesson.tmpl"}},
+			want:   settingsFlags{templates: customUserTemplates{"a/course.tmpl", "b/lesson.tmpl"}},
+		},
+		{
+			name:   "bool",
+			values: map[string]any{"naming.one_based": true},
+			want:   settingsFlags{oneBased: true},
+		},
+		{
+			name:   "int",
+			values: map[string]any{"naming.pad": int64(3)},
+			want:   settingsFlags{pad: 3},
+		},
+		{
+			name:   "string",
+			values: map[string]any{"output_dir": "/notes"},
+			want:   settingsFlags{outputDir: "/notes"},
+		},
+		{
+			name:   "given flags win",
+			args:   []string{"-pad", "2", "-o", "/cli", "-t", "cli"},
+			values: map[string]any{"naming.pad": int64(3), "output_dir": "/notes", "tags": []string{"go"}, "naming.one_based": true},
+			want:   settingsFlags{outputDir: "/cli", tags: tags{"cli"}, oneBased: true, pad: 2},
+		},
+	}
+
+	for _, c := range configTests {
+		t.Run(c.name, func(t *testing.T) {
+			flagSet, flags := newSettingsFlagSet()
+			if err := flagSet.Parse(c.args); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := config.Config{Values: make(map[string]config.Value)}
+			for key, value := range c.values {
+				loaded.Values[key] = config.Value{Value: value, Source: "config.toml"}
+			}
+
+			if err := applyConfig(flagSet, loaded); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(*flags, c.want) {
+				t.Errorf("got %+v, want %+v", *flags, c.want)
+			}
+		})
+	}
+}
+
+func TestApplyConfig_Error(t *testing.T) {
+	flagSet, _ := newSettingsFlagSet()
+	loaded := config.Config{Values: map[string]config.Value{
+		"templates": {Value: []string{"notes.tmpl"}, Source: "config.toml"},
+	}}
+
+	err := applyConfig(flagSet, loaded)
+	if err == nil || !strings.HasPrefix(err.Error(), "templates (config.toml): ") {
+		t.Errorf("got %v, want an error naming the key and its source", err)
+	}
+}
+
+func TestDefaultConfigValue(t *testing.T) {
+	defaultTests := []struct {
+		name string
+		want any
+	}{
+		{"tags", []string{}},
+		{"flavor", templater.DefaultFlavor},
+		{"naming.one_based", false},
+		{"naming.pad", int64(0)},
+		{"cache.enabled", true},
+	}
+
+	for _, c := range defaultTests {
+		t.Run(c.name, func(t *testing.T) {
+			key, ok := config.LookupKey(c.name)
+			if !ok {
+				t.Fatalf("unknown key %s", c.name)
+			}
+
+			value := defaultConfigValue(key)
+			if !reflect.DeepEqual(value.Value, c.want) {
+				t.Errorf("got %#v, want %#v", value.Value, c.want)
+			}
+
+			if value.Source != "default" {
+				t.Errorf("got source %q, want %q", value.Source, "default")
+			}
+		})
+	}
+}
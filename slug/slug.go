@@ -0,0 +1,147 @@
+// Package slug turns titles into URL and file name friendly slugs.
+package slug
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options configures Make.
+type Options struct {
+	// Replacements applied to the title before it is slugified, such as
+	// "&" to "and". Longer keys are replaced first.
+	Replacements map[string]string
+	// Maximum length of the slug in bytes, 0 for no maximum. Slugs are
+	// truncated at a word boundary when possible.
+	MaxLength int
+	// Drop the characters that can not be transliterated to ASCII, instead
+	// of keeping them as they are.
+	ASCII bool
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Replacements: map[string]string{
+			"&":  "and",
+			"(":  "_",
+			")":  "_",
+			"[":  "_",
+			"]":  "_",
+			"/":  "_",
+			"\\": "_",
+			"|":  "_",
+		},
+	}
+}
+
+// Returns the slug of title: lower case, transliterated to ASCII where
+// possible, with every run of spaces and punctuation collapsed to a single
+// hyphen.
+func Make(title string, options Options) string {
+	title = replacer(options.Replacements).Replace(strings.TrimSpace(title))
+
+	var result strings.Builder
+	separator := false
+
+	for _, char := range strings.ToLower(title) {
+		var mapped string
+
+		switch transliterated, ok := transliterations[char]; {
+		case ok:
+			mapped = transliterated
+		case unicode.Is(unicode.Mn, char):
+			// Combining marks belong to the previous character.
+			if !options.ASCII {
+				mapped = string(char)
+			}
+		case char == '_', unicode.IsLetter(char), unicode.IsDigit(char):
+			if char < utf8.RuneSelf || !options.ASCII {
+				mapped = string(char)
+			} else {
+				separator = true
+			}
+		default:
+			separator = true
+		}
+
+		if mapped == "" {
+			continue
+		}
+
+		if separator && result.Len() > 0 {
+			result.WriteByte('-')
+		}
+		separator = false
+		result.WriteString(mapped)
+	}
+
+	return truncate(result.String(), options.MaxLength)
+}
+
+// Returns a strings.Replacer for replacements, with longer keys taking
+// precedence.
+func replacer(replacements map[string]string) *strings.Replacer {
+	keys := slices.SortedFunc(maps.Keys(replacements), func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+
+	var oldnew []string
+	for _, key := range keys {
+		if key != "" {
+			oldnew = append(oldnew, key, replacements[key])
+		}
+	}
+
+	return strings.NewReplacer(oldnew...)
+}
+
+// Truncates slug to maxLength bytes, cutting at the last hyphen when the
+// cut would split a word.
+func truncate(slug string, maxLength int) string {
+	if maxLength <= 0 || len(slug) <= maxLength {
+		return slug
+	}
+
+	cut := maxLength
+	for cut > 0 && !utf8.RuneStart(slug[cut]) {
+		cut--
+	}
+
+	truncated := slug[:cut]
+	if slug[cut] != '-' {
+		if lastHyphen := strings.LastIndexByte(truncated, '-'); lastHyphen > 0 {
+			truncated = truncated[:lastHyphen]
+		}
+	}
+
+	return strings.TrimRight(truncated, "-")
+}
+
+// Deduper makes slugs unique by appending -2, -3, ... to the slugs it has
+// already seen. The result only depends on the order of the calls.
+type Deduper struct {
+	seen map[string]bool
+}
+
+func NewDeduper() *Deduper {
+	return &Deduper{
+		seen: make(map[string]bool),
+	}
+}
+
+// Returns slug, or slug with the first free numeric suffix if it was
+// already returned before.
+func (deduper *Deduper) Unique(slug string) string {
+	unique := slug
+	for x := 2; deduper.seen[unique]; x++ {
+		unique = fmt.Sprintf("%s-%d", slug, x)
+	}
+
+	deduper.seen[unique] = true
+	return unique
+}
@@ -0,0 +1,60 @@
+package slug
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	slugTests := []struct {
+		title   string
+		options Options
+		want    string
+	}{
+		{"Portfolio Footer & Projects", DefaultOptions(), "portfolio-footer-and-projects"},
+		{"Adding a Light/Dark Theme Switcher", DefaultOptions(), "adding-a-light_dark-theme-switcher"},
+		{"Adding a Light|Dark Theme Switcher", DefaultOptions(), "adding-a-light_dark-theme-switcher"},
+		{"Adding a Light\\Dark Theme Switcher", DefaultOptions(), "adding-a-light_dark-theme-switcher"},
+		{"Routing Q&A", DefaultOptions(), "routing-qanda"},
+		{"Protecting Client-Side Routes", DefaultOptions(), "protecting-client-side-routes"},
+		{" Scaffolding an API Project ", DefaultOptions(), "scaffolding-an-api-project"},
+		{"Search, Filter, & Sort", DefaultOptions(), "search-filter-and-sort"},
+		{"Search -- Filter -- Sort", DefaultOptions(), "search-filter-sort"},
+		{"  ...Wrapping up!  ", DefaultOptions(), "wrapping-up"},
+		{"Café Crème Brûlée", DefaultOptions(), "cafe-creme-brulee"},
+		{"Straße & Œuvre", DefaultOptions(), "strasse-and-oeuvre"},
+		{"Объекты в JavaScript", DefaultOptions(), "obekty-v-javascript"},
+		{"Λάμδα", DefaultOptions(), "lamda"},
+		{"日本語 Intro", DefaultOptions(), "日本語-intro"},
+		{"日本語 Intro", Options{ASCII: true}, "intro"},
+		{"Café", Options{ASCII: true}, "cafe"},
+		{"C++ & C#", Options{Replacements: map[string]string{"C++": "cpp", "C#": "csharp", "&": "and"}}, "cpp-and-csharp"},
+		{"Introduction to TypeScript Generics", Options{MaxLength: 20}, "introduction-to"},
+		{"Introduction to TypeScript", Options{MaxLength: 15}, "introduction-to"},
+		{"Supercalifragilistic", Options{MaxLength: 5}, "super"},
+	}
+
+	for _, c := range slugTests {
+		t.Run(c.title, func(t *testing.T) {
+			answer := Make(c.title, c.options)
+
+			if answer != c.want {
+				t.Errorf("got %s, want %s", answer, c.want)
+			}
+		})
+	}
+}
+
+func TestDeduper_Unique(t *testing.T) {
+	deduper := NewDeduper()
+
+	var answer []string
+	for _, slug := range []string{"intro", "qanda", "intro", "intro-2", "intro", "qanda"} {
+		answer = append(answer, deduper.Unique(slug))
+	}
+
+	want := []string{"intro", "qanda", "intro-2", "intro-2-2", "intro-3", "qanda-2"}
+	if !reflect.DeepEqual(answer, want) {
+		t.Errorf("got %v, want %v", answer, want)
+	}
+}
@@ -0,0 +1,62 @@
+package slug
+
+// transliterations maps lower case runes to their ASCII spelling.
+var transliterations = map[rune]string{}
+
+func init() {
+	groups := []struct {
+		from string
+		to   []string
+	}{
+		// Latin-1 Supplement and Latin Extended-A.
+		{"àáâãäåāăą", []string{"a"}},
+		{"æ", []string{"ae"}},
+		{"çćĉċč", []string{"c"}},
+		{"ďđð", []string{"d"}},
+		{"èéêëēĕėęě", []string{"e"}},
+		{"ĝğġģ", []string{"g"}},
+		{"ĥħ", []string{"h"}},
+		{"ìíîïĩīĭįı", []string{"i"}},
+		{"ĳ", []string{"ij"}},
+		{"ĵ", []string{"j"}},
+		{"ķĸ", []string{"k"}},
+		{"ĺļľŀł", []string{"l"}},
+		{"ñńņňŉŋ", []string{"n"}},
+		{"òóôõöøōŏő", []string{"o"}},
+		{"œ", []string{"oe"}},
+		{"ŕŗř", []string{"r"}},
+		{"śŝşšſș", []string{"s"}},
+		{"ß", []string{"ss"}},
+		{"ţťŧț", []string{"t"}},
+		{"þ", []string{"th"}},
+		{"ùúûüũūŭůűų", []string{"u"}},
+		{"ŵ", []string{"w"}},
+		{"ýÿŷ", []string{"y"}},
+		{"źżž", []string{"z"}},
+		// Greek.
+		{"αβγδεζηθικλμνξοπρσςτυφχψω", []string{
+			"a", "v", "g", "d", "e", "z", "i", "th", "i", "k", "l", "m", "n",
+			"x", "o", "p", "r", "s", "s", "t", "y", "f", "ch", "ps", "o",
+		}},
+		{"άέήίόύώϊϋΐΰ", []string{"a", "e", "i", "i", "o", "y", "o", "i", "y", "i", "y"}},
+		// Cyrillic.
+		{"абвгдеёжзийклмнопрстуфхцчшщъыьэюя", []string{
+			"a", "b", "v", "g", "d", "e", "yo", "zh", "z", "i", "y", "k", "l",
+			"m", "n", "o", "p", "r", "s", "t", "u", "f", "kh", "ts", "ch", "sh",
+			"shch", "", "y", "", "e", "yu", "ya",
+		}},
+		{"єіїґў", []string{"ye", "i", "yi", "g", "u"}},
+	}
+
+	for _, group := range groups {
+		x := 0
+		for _, char := range group.from {
+			if len(group.to) == 1 {
+				transliterations[char] = group.to[0]
+			} else {
+				transliterations[char] = group.to[x]
+			}
+			x++
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package tag normalizes and validates Obsidian tags.
+package tag
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// InvalidTagError is returned for tags that Obsidian would not accept.
+type InvalidTagError struct {
+	Tag    string
+	Reason string
+}
+
+func (err *InvalidTagError) Error() string {
+	return fmt.Sprintf("invalid tag %q: %s", err.Tag, err.Reason)
+}
+
+// Returns tag normalized: trimmed, without its leading "#", and with every
+// run of spaces replaced with a single hyphen. Returns an
+// *InvalidTagError if the result is still not a valid Obsidian tag, which
+// is made of letters, numbers, "_", "-" and "/" separating nested tags,
+// and is not only made of numbers.
+func Normalize(tag string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.TrimLeft(strings.TrimSpace(tag), "#")), "-")
+
+	if normalized == "" {
+		return "", &InvalidTagError{tag, "empty tag"}
+	}
+
+	for _, char := range normalized {
+		switch {
+		case unicode.IsLetter(char), unicode.IsNumber(char), unicode.Is(unicode.Mn, char):
+		case char == '_', char == '-', char == '/':
+		default:
+			return "", &InvalidTagError{tag, fmt.Sprintf("%q is not allowed, only letters, numbers, _, - and / are", char)}
+		}
+	}
+
+	for part := range strings.SplitSeq(normalized, "/") {
+		if part == "" {
+			return "", &InvalidTagError{tag, "nested tags can not be empty"}
+		}
+	}
+
+	if strings.IndexFunc(normalized, func(char rune) bool { return !unicode.IsNumber(char) }) == -1 {
+		return "", &InvalidTagError{tag, "tags can not be only made of numbers"}
+	}
+
+	return normalized, nil
+}
+
+// Returns the normalized tags of a comma separated list, without empty
+// entries and duplicates.
+func ParseList(list string) ([]string, error) {
+	var tags []string
+
+	for entry := range strings.SplitSeq(list, ",") {
+		if strings.Trim(entry, " \t#") == "" {
+			continue
+		}
+
+		normalized, err := Normalize(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(tags, normalized) {
+			tags = append(tags, normalized)
+		}
+	}
+
+	return tags, nil
+}
+
+// Returns the nested tag made of parts, skipping empty parts, such as
+// "frontend-masters/course/section".
+func Join(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(slices.Clone(parts), func(part string) bool {
+		return part == ""
+	}), "/")
+}
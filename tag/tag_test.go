@@ -0,0 +1,64 @@
+package tag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	normalizeTests := []struct {
+		tag  string
+		want string
+		err  bool
+	}{
+		{"go", "go", false},
+		{"  #go  ", "go", false},
+		{"##web dev", "web-dev", false},
+		{"front end/react  hooks", "front-end/react-hooks", false},
+		{"résumé_2024", "résumé_2024", false},
+		{"", "", true},
+		{"#", "", true},
+		{"2024", "", true},
+		{"go:basics", "", true},
+		{"c++", "", true},
+		{"a//b", "", true},
+		{"/go", "", true},
+	}
+
+	for _, c := range normalizeTests {
+		t.Run(c.tag, func(t *testing.T) {
+			got, err := Normalize(c.tag)
+
+			var invalidTag *InvalidTagError
+			if c.err != errors.As(err, &invalidTag) {
+				t.Fatalf("got error %v, want error %v", err, c.err)
+			}
+
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	got, err := ParseList(" #go, web dev,, go ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"go", "web-dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := ParseList("go, c++"); err == nil {
+		t.Error("got no error for an invalid tag")
+	}
+}
+
+func TestJoin(t *testing.T) {
+	if got, want := Join("frontend-masters", "", "go", "intro"), "frontend-masters/go/intro"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
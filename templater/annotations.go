@@ -0,0 +1,185 @@
+package templater
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/raphaeltannous/fem-helper/api"
+)
+
+// Styles the default annotation.tmpl can render annotations with.
+var AnnotationStyles = []string{"callout", "list", "table", "timeline"}
+
+// AnnotationOptions configures how annotations are rendered.
+type AnnotationOptions struct {
+	// One of AnnotationStyles.
+	Style string
+	// Obsidian callout type of the callout style, such as NOTE or TIP.
+	Callout string
+	// Folding of the callout style: "+" for expanded, "-" for collapsed, or
+	// "" for callouts that can not be folded.
+	Fold string
+	// Format of the annotation times.
+	Timestamps api.TimestampFormat
+	// Merge annotations with overlapping ranges, see api.ProcessOptions.
+	Merge bool
+	// Pick the callout type of each annotation from its kind, see
+	// KindCallouts, instead of using Callout for all of them.
+	Classify bool
+}
+
+// Callout types of the annotation kinds when classifying. Notes use
+// AnnotationOptions.Callout.
+var KindCallouts = map[api.AnnotationKind]string{
+	api.AnnotationTip:     "TIP",
+	api.AnnotationWarning: "WARNING",
+	api.AnnotationLink:    "INFO",
+	api.AnnotationCode:    "EXAMPLE",
+}
+
+func DefaultAnnotationOptions() AnnotationOptions {
+	return AnnotationOptions{
+		Style:   "callout",
+		Callout: "NOTE",
+		Fold:    "+",
+
+		Timestamps: api.TimestampClock,
+	}
+}
+
+func (options AnnotationOptions) validate() error {
+	if !slices.Contains(AnnotationStyles, options.Style) {
+		return fmt.Errorf("unknown annotation style %q, expected one of: %s", options.Style, strings.Join(AnnotationStyles, ", "))
+	}
+
+	if !slices.Contains([]string{"+", "-", ""}, options.Fold) {
+		return fmt.Errorf("unknown callout folding %q, expected +, - or an empty string", options.Fold)
+	}
+
+	if strings.ContainsAny(options.Callout, "[]\n") {
+		return fmt.Errorf("invalid callout type %q", options.Callout)
+	}
+
+	if err := options.Timestamps.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AnnotationsContext is the data the "annotations" template of
+// annotation.tmpl is executed with. Templates get one with
+// {{ template "annotations" (annotations .Annotations .WatchURL) }}.
+type AnnotationsContext struct {
+	AnnotationOptions
+	// Player URL of the lesson, empty if unknown.
+	URL         string
+	Annotations []AnnotationContext
+}
+
+// AnnotationContext is a single annotation, with the player URL that
+// seeks to its start.
+type AnnotationContext struct {
+	api.AnnotationData
+	// Empty if the lesson URL or the start of the annotation is unknown.
+	URL string
+	// Format of Times.
+	Format api.TimestampFormat
+
+	// Kind of the annotation guessed from its message.
+	Kind api.AnnotationKind
+	// Callout type of the annotation, by kind when classifying.
+	Callout string
+	// URLs and inline code spans found in the message.
+	URLs []string
+	Code []string
+}
+
+// Returns the range of the annotation in the configured timestamp format.
+// Other formats are available with .FormatRange. Invalid ranges, see
+// api.AnnotationData.ValidateRange, give their start alone, or no times
+// if the start is unknown too.
+func (anno AnnotationContext) Times() []string {
+	annotation := anno.AnnotationData
+	if annotation.ValidateRange() != nil {
+		if len(annotation.Range) == 0 || annotation.Range[0] < 0 {
+			return nil
+		}
+		annotation.Range = annotation.Range[:1]
+	}
+
+	times, err := annotation.FormatRange(anno.Format)
+	if err != nil {
+		return annotation.GetReadableRange()
+	}
+
+	return times
+}
+
+// Returns the AnnotationsContext of annos, sorted and deduplicated, linked
+// to the lesson player at lessonURL if one is given.
+func (markdown MarkdownTemplater) annotationsContext(annos api.Annotations, lessonURL ...string) AnnotationsContext {
+	annos = annos.Process(api.ProcessOptions{Merge: markdown.annotations.Merge})
+
+	context := AnnotationsContext{
+		AnnotationOptions: markdown.annotations,
+		Annotations:       make([]AnnotationContext, len(annos)),
+	}
+	if len(lessonURL) > 0 {
+		context.URL = lessonURL[0]
+	}
+
+	for x, anno := range annos {
+		context.Annotations[x] = AnnotationContext{
+			AnnotationData: anno,
+			URL:            annotationURL(context.URL, anno),
+			Format:         context.Timestamps,
+
+			Kind:    anno.Kind(),
+			Callout: context.Callout,
+			URLs:    anno.URLs(),
+			Code:    anno.Code(),
+		}
+		if callout, ok := KindCallouts[context.Annotations[x].Kind]; ok && context.Classify {
+			context.Annotations[x].Callout = callout
+		}
+	}
+
+	return context
+}
+
+// Renders annotations, api.Annotations or an AnnotationsContext, as
+// expanded Obsidian NOTE callouts, whose timestamps link to the player
+// when their URL is known. Kept for custom templates, annotation.tmpl is
+// used by the default templates.
+func formatAnnotationsToMarkdown(annotations any) (string, error) {
+	var annos []AnnotationContext
+
+	switch annotations := annotations.(type) {
+	case api.Annotations:
+		for _, anno := range annotations {
+			annos = append(annos, AnnotationContext{AnnotationData: anno, Format: api.TimestampClock})
+		}
+	case AnnotationsContext:
+		annos = annotations.Annotations
+	default:
+		return "", fmt.Errorf("formatannotations: expected annotations, got %T", annotations)
+	}
+
+	var result strings.Builder
+
+	for _, anno := range annos {
+		readableRange := strings.Join(anno.Times(), " -> ")
+		if anno.URL != "" && readableRange != "" {
+			readableRange = fmt.Sprintf("[%s](%s)", readableRange, escapeLinkTarget(anno.URL))
+		}
+
+		result.WriteString(
+			fmt.Sprintf("\n> [!NOTE]+ %s\n", readableRange),
+		)
+		result.WriteString(quoteMarkdown(anno.Message) + "\n")
+	}
+
+	return result.String(), nil
+}
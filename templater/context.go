@@ -0,0 +1,240 @@
+package templater
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/yaml"
+)
+
+// LessonContext is the data lesson templates are executed with.
+//
+// The lesson fields (.Title, .Index, .Annotations, ...) are available
+// directly, the rest of the course structure through the other fields.
+type LessonContext struct {
+	api.LessonData
+	// Number of the lesson as used in names (1-based with --one-based).
+	Number int
+	Tags   []string
+	// Same as .Course.Slug.
+	CourseSlug string
+	// Same as .Course.Tag.
+	CourseTag string
+	// Properties of the lesson note.
+	Frontmatter yaml.Map
+
+	Course   CourseContext
+	Section  SectionContext
+	Position PositionContext
+
+	// Path of the lesson note, relative to the output root.
+	Path string
+	// Previous and next lessons in course order, nil at the ends.
+	Prev *LessonLink
+	Next *LessonLink
+
+	// Duration of the lesson, zero if unknown.
+	Duration time.Duration
+	// Player URL of the lesson.
+	WatchURL string
+}
+
+// CourseContext describes the course a note belongs to.
+type CourseContext struct {
+	Slug        string
+	Title       string
+	Description string
+	// Zero if unknown.
+	Published time.Time
+	Duration  time.Duration
+	// Path of the course note, relative to the output root.
+	Path string
+	// Player URL of the course.
+	URL string
+	// Tag of the course, see Options.TagPrefix.
+	Tag string
+}
+
+// SectionContext describes the section a lesson belongs to.
+type SectionContext struct {
+	// Index of the section in the course, and its number as used in names
+	// (1-based with --one-based).
+	Index  int
+	Number int
+	Title  string
+	Slug   string
+	// Zero if unknown.
+	Duration time.Duration
+	// Folder of the section relative to the output root, empty for flat
+	// layouts.
+	Path string
+	// Path of the section note, empty if section notes are not generated.
+	Note string
+	// Nested tag of the section under the course tag.
+	Tag string
+}
+
+// SectionNoteContext is the data section templates are executed with.
+type SectionNoteContext struct {
+	SectionContext
+	Tags   []string
+	Course CourseContext
+	// Properties of the section note.
+	Frontmatter yaml.Map
+
+	// Lessons of the section in order.
+	Lessons []LessonLink
+	// Annotations of the section, grouped by lesson. Lessons without
+	// annotations are left out.
+	Annotations []LessonAnnotations
+}
+
+// LessonAnnotations are the annotations of a single lesson.
+type LessonAnnotations struct {
+	Lesson      LessonLink
+	Annotations api.Annotations
+}
+
+// PositionContext is the 1-based position of a lesson in the course and
+// in its section. It prints as "3 of 12".
+type PositionContext struct {
+	Number int
+	Total  int
+
+	NumberInSection int
+	SectionTotal    int
+}
+
+func (position PositionContext) String() string {
+	return fmt.Sprintf("%d of %d", position.Number, position.Total)
+}
+
+// LessonLink points to another lesson note.
+type LessonLink struct {
+	// Index of the lesson, and its number as used in names (1-based with
+	// --one-based).
+	Index  int
+	Number int
+	Title  string
+	// Path of the lesson note, relative to the output root.
+	Path string
+	// Player URL of the lesson.
+	WatchURL string
+}
+
+// Returns the context of the course note.
+func (markdown MarkdownTemplater) courseContext() CourseContext {
+	course := markdown.course.CourseData
+
+	return CourseContext{
+		Slug:        course.Slug,
+		Title:       course.Title,
+		Description: course.Description,
+		Published:   course.Published,
+		Duration:    course.Duration(),
+		Path:        markdown.layout.CourseFile,
+		URL:         markdown.courseURL(),
+		Tag:         markdown.courseTag(),
+	}
+}
+
+// Returns the context lesson is rendered with.
+func (markdown MarkdownTemplater) lessonContext(position api.Position, lesson api.LessonData) LessonContext {
+	course := markdown.courseContext()
+
+	lessonContext := LessonContext{
+		LessonData: lesson,
+		Number:     markdown.naming.Number(lesson.Index),
+		Tags:       markdown.course.Tags,
+		CourseSlug: course.Slug,
+		CourseTag:  course.Tag,
+
+		Course:  course,
+		Section: markdown.sectionContext(position.SectionIndex),
+		Position: PositionContext{
+			Number:          position.Global + 1,
+			Total:           position.Total,
+			NumberInSection: position.InSection + 1,
+			SectionTotal:    position.SectionTotal,
+		},
+
+		Path: markdown.layout.LessonFiles[lesson.Index],
+		Prev: markdown.lessonLink(position.Prev),
+		Next: markdown.lessonLink(position.Next),
+
+		Duration: lesson.Duration,
+		WatchURL: markdown.lessonURL(lesson),
+	}
+	lessonContext.Frontmatter = markdown.frontmatter(
+		fmt.Sprintf("%d. %s", lessonContext.Number, lesson.Title),
+		markdown.noteTag(position.SectionIndex),
+		markdown.lessonProperties(lessonContext),
+	)
+
+	return lessonContext
+}
+
+// Returns the context of the section at index x.
+func (markdown MarkdownTemplater) sectionContext(x int) SectionContext {
+	section := markdown.course.Sections[x]
+
+	sectionContext := SectionContext{
+		Index:    x,
+		Number:   markdown.naming.Number(x),
+		Title:    section.Title,
+		Slug:     markdown.layout.SectionSlugs[x],
+		Duration: section.Duration,
+		Path:     markdown.layout.SectionDirs[x],
+		Tag:      markdown.sectionTag(x),
+	}
+	if markdown.hasSectionNote(x) {
+		sectionContext.Note = markdown.layout.SectionFiles[x]
+	}
+
+	return sectionContext
+}
+
+// Returns the context the section note of the section at index x is
+// rendered with.
+func (markdown MarkdownTemplater) sectionNoteContext(x int) SectionNoteContext {
+	sectionNote := SectionNoteContext{
+		SectionContext: markdown.sectionContext(x),
+		Tags:           markdown.course.Tags,
+		Course:         markdown.courseContext(),
+	}
+	sectionNote.Frontmatter = markdown.frontmatter(
+		fmt.Sprintf("%d. %s", sectionNote.Number, sectionNote.Title),
+		markdown.noteTag(x),
+		nil,
+	)
+
+	for _, lesson := range markdown.course.Section(x) {
+		link := markdown.lessonLink(&lesson)
+		sectionNote.Lessons = append(sectionNote.Lessons, *link)
+
+		if len(lesson.Annotations) > 0 {
+			sectionNote.Annotations = append(sectionNote.Annotations, LessonAnnotations{
+				Lesson:      *link,
+				Annotations: lesson.Annotations,
+			})
+		}
+	}
+
+	return sectionNote
+}
+
+func (markdown MarkdownTemplater) lessonLink(lesson *api.LessonData) *LessonLink {
+	if lesson == nil {
+		return nil
+	}
+
+	return &LessonLink{
+		Index:  lesson.Index,
+		Number: markdown.naming.Number(lesson.Index),
+		Title:  lesson.Title,
+		Path:   markdown.layout.LessonFiles[lesson.Index],
+
+		WatchURL: markdown.lessonURL(*lesson),
+	}
+}
@@ -0,0 +1,103 @@
+package templater
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/raphaeltannous/fem-helper/markup"
+)
+
+// Characters that can not be escaped in wikilink targets: they start a
+// heading or block reference, the label, or end the link.
+const wikilinkTargetSpecials = "#^|[]"
+
+// Returns an Obsidian wikilink to target, with label if one is given.
+// Targets that a wikilink can not hold are linked with a markdown link
+// instead.
+func wikilink(target string, label ...string) string {
+	var text string
+	if len(label) > 0 {
+		text = label[0]
+	}
+
+	if strings.ContainsAny(target, wikilinkTargetSpecials) {
+		if text == "" {
+			text = strings.TrimSuffix(path.Base(target), ".md")
+		}
+
+		return mdlink(text, target)
+	}
+
+	if text == "" {
+		return fmt.Sprintf("[[%s]]", target)
+	}
+
+	return fmt.Sprintf("[[%s|%s]]", target, escapeLinkLabel(text))
+}
+
+// Returns a markdown link to target, a URL or a note path.
+func mdlink(label, target string) string {
+	return fmt.Sprintf("[%s](%s)", escapeInline(label), escapeLinkTarget(target))
+}
+
+// Makes label safe as the label of a wikilink, where "|" and brackets can
+// not be escaped: "|" becomes "-", and brackets become parentheses.
+func escapeLinkLabel(label string) string {
+	return strings.NewReplacer(
+		"|", "-",
+		"[", "(",
+		"]", ")",
+		"\n", " ",
+	).Replace(label)
+}
+
+// Makes target safe as the target of a markdown link. Note paths are
+// percent encoded, and URLs only get the characters that would end the
+// link encoded.
+func escapeLinkTarget(target string) string {
+	if strings.Contains(target, "://") {
+		return markup.EscapeURL(target)
+	}
+
+	segments := strings.Split(target, "/")
+	for x, segment := range segments {
+		segments[x] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Escapes the characters of text that markdown or Obsidian would read as
+// formatting, tags, links or math.
+func escapeInline(text string) string {
+	return markup.EscapeMarkdown(text)
+}
+
+// Makes text safe as the text of a single line heading.
+func escapeHeading(text string) string {
+	return escapeInline(strings.Join(strings.Fields(text), " "))
+}
+
+// Prefixes every line of text with "> ", so multi-line text stays inside
+// its blockquote or callout.
+func quoteMarkdown(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	for x, line := range lines {
+		if line == "" {
+			lines[x] = ">"
+		} else {
+			lines[x] = "> " + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Makes text fit in a single markdown table cell.
+func tableCell(text string) string {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "|", "\\|")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
@@ -0,0 +1,51 @@
+package templater
+
+import (
+	"testing"
+)
+
+func TestWikilink(t *testing.T) {
+	wikilinkTests := []struct {
+		target string
+		label  string
+		want   string
+	}{
+		{"0-intro/0-welcome.md", "0. Welcome", "[[0-intro/0-welcome.md|0. Welcome]]"},
+		{"0-intro/0-welcome.md", "", "[[0-intro/0-welcome.md]]"},
+		{"0-intro/0-welcome.md", "A | B [draft]", "[[0-intro/0-welcome.md|A - B (draft)]]"},
+		{"0-intro/0-C# basics.md", "C# basics", "[C\\# basics](0-intro/0-C%23%20basics.md)"},
+		{"0-intro/0-C# basics.md", "", "[0-C\\# basics](0-intro/0-C%23%20basics.md)"},
+	}
+
+	for _, c := range wikilinkTests {
+		t.Run(c.want, func(t *testing.T) {
+			if got := wikilink(c.target, c.label); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestEscapes(t *testing.T) {
+	escapeTests := []struct {
+		name   string
+		escape func(string) string
+		text   string
+		want   string
+	}{
+		{"heading", escapeHeading, "C# and\n*Go* ^1", "C\\# and \\*Go\\* \\^1"},
+		{"inline", escapeInline, "$5 [[note]] a_b", "\\$5 \\[\\[note\\]\\] a\\_b"},
+		{"url", escapeLinkTarget, "https://example.com/a (b)", "https://example.com/a%20%28b%29"},
+		{"path", escapeLinkTarget, "0-intro/a b?.md", "0-intro/a%20b%3F.md"},
+		{"table cell", tableCell, "a | b\nc", "a \\| b<br>c"},
+		{"quote", quoteMarkdown, "a\n\nb", "> a\n>\n> b"},
+	}
+
+	for _, c := range escapeTests {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.escape(c.text); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package templater
+
+import (
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/yaml"
+)
+
+// CourseNoteContext is the data course templates are executed with.
+type CourseNoteContext struct {
+	api.CourseData
+	Tags []string
+	// Tag of the course, see Options.TagPrefix.
+	CourseTag string
+	// Path of the progress view, empty if none is generated.
+	Progress string
+
+	// Properties of the course note, see frontmatter.
+	Frontmatter yaml.Map
+}
+
+// Returns the frontmatter of a note with alias, noteTag and properties,
+// followed by the extra keys of the options, which replace the keys they
+// share.
+func (markdown MarkdownTemplater) frontmatter(alias, noteTag string, properties yaml.Map) yaml.Map {
+	frontmatter := yaml.Map{
+		{Key: "aliases", Value: []string{alias}},
+		{Key: "tags", Value: append([]string{noteTag}, markdown.course.Tags...)},
+	}
+	frontmatter = append(frontmatter, properties...)
+
+	for _, item := range markdown.extraFrontmatter {
+		frontmatter.Set(item.Key, item.Value)
+	}
+
+	return frontmatter
+}
+
+// Returns properties as a frontmatter block, between "---" lines.
+func formatFrontmatter(properties yaml.Map) string {
+	if len(properties) == 0 {
+		return "---\n---\n"
+	}
+
+	return "---\n" + yaml.Marshal(properties) + "---\n"
+}
+
+// Returns the context the course note is rendered with.
+func (markdown MarkdownTemplater) courseNoteContext() CourseNoteContext {
+	return CourseNoteContext{
+		CourseData:  markdown.course.CourseData,
+		Tags:        markdown.course.Tags,
+		CourseTag:   markdown.courseTag(),
+		Progress:    markdown.progressFile,
+		Frontmatter: markdown.frontmatter(markdown.course.Title, markdown.courseTag(), markdown.courseProperties()),
+	}
+}
@@ -0,0 +1,226 @@
+package templater
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/description"
+	"github.com/raphaeltannous/fem-helper/markup"
+	"github.com/raphaeltannous/fem-helper/slug"
+	"github.com/raphaeltannous/fem-helper/yaml"
+)
+
+// General helpers available to every template, see the README for their
+// documentation. Helpers that depend on the options of a MarkdownTemplater
+// are added by NewMarkdownTemplater.
+var markdownTemplateFunctions = template.FuncMap{
+	"formattags":        formatTagsToMarkdown,
+	"formatannotations": formatAnnotationsToMarkdown,
+	"quote":             quoteMarkdown,
+	"tablecell":         tableCell,
+
+	"duration":    formatDuration,
+	"date":        formatDate,
+	"timestamp":   formatTimestamp,
+	"wikilink":    wikilink,
+	"mdlink":      mdlink,
+	"linklabel":   escapeLinkLabel,
+	"linktarget":  escapeLinkTarget,
+	"heading":     escapeHeading,
+	"inline":      escapeInline,
+	"yaml":        yaml.Scalar,
+	"toyaml":      yaml.Marshal,
+	"frontmatter": formatFrontmatter,
+	"join":        join,
+	"default":     defaultValue,
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+	"title":       title,
+	"indent":      indent,
+	"truncate":    truncate,
+}
+
+// Returns the helpers that depend on the options of markdown.
+func (markdown MarkdownTemplater) functions() template.FuncMap {
+	return template.FuncMap{
+		"formatcoursedata": markdown.formatCourseDataToMarkdown,
+		"annotations":      markdown.annotationsContext,
+		"slugify": func(text string) string {
+			return slug.Make(text, markdown.naming.Slug)
+		},
+		"pad": markdown.naming.Pad,
+		"description": func(text string, format ...description.Format) (string, error) {
+			options := description.Options{Format: markdown.descriptionFormat, BaseURL: markdown.baseURL}
+			if len(format) > 0 {
+				options.Format = format[0]
+			}
+
+			return description.Convert(text, options)
+		},
+	}
+}
+
+// Converts value, a time.Duration or a number of seconds, to a duration.
+func toDuration(value any) (time.Duration, error) {
+	if duration, ok := value.(time.Duration); ok {
+		return duration, nil
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(reflected.Int()) * time.Second, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(reflected.Uint()) * time.Second, nil
+	case reflect.Float32, reflect.Float64:
+		return time.Duration(reflected.Float() * float64(time.Second)), nil
+	}
+
+	return 0, fmt.Errorf("expected a duration or a number of seconds, got %T", value)
+}
+
+// Returns value, a time.Duration or a number of seconds, as "1h 2m 3s",
+// leaving out the zero units.
+func formatDuration(value any) (string, error) {
+	duration, err := toDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("duration: %w", err)
+	}
+
+	duration = duration.Round(time.Second)
+	if duration == 0 {
+		return "0s", nil
+	}
+
+	var parts []string
+	if duration < 0 {
+		parts = append(parts, "-")
+		duration = -duration
+	}
+
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, unit := range units {
+		if count := duration / unit.size; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", count, unit.name))
+			duration -= count * unit.size
+		}
+	}
+
+	return strings.Replace(strings.Join(parts, " "), "- ", "-", 1), nil
+}
+
+// Returns value, a time.Duration or a number of seconds, as a video
+// timestamp in format, "clock" by default: "MM:SS", or "H:MM:SS" from an
+// hour on.
+func formatTimestamp(value any, format ...api.TimestampFormat) (string, error) {
+	duration, err := toDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("timestamp: %w", err)
+	}
+
+	timestampFormat := api.TimestampClock
+	if len(format) > 0 {
+		timestampFormat = format[0]
+	}
+
+	return api.FormatTimestamp(int(duration.Round(time.Second)/time.Second), timestampFormat)
+}
+
+// Returns date formatted with layout, or an empty string if date is
+// unknown.
+func formatDate(layout string, date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(layout)
+}
+
+// Joins elements, which can be a slice of any type, with sep.
+func join(elements any, sep string) (string, error) {
+	value := reflect.ValueOf(elements)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: expected a slice, got %T", elements)
+	}
+
+	texts := make([]string, value.Len())
+	for x := range texts {
+		texts[x] = fmt.Sprint(value.Index(x).Interface())
+	}
+
+	return strings.Join(texts, sep), nil
+}
+
+// Returns value, or fallback if value is empty: nil, the zero value of its
+// type, or an empty slice or map.
+func defaultValue(fallback, value any) any {
+	if value == nil {
+		return fallback
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if reflected.Len() == 0 {
+			return fallback
+		}
+	default:
+		if reflected.IsZero() {
+			return fallback
+		}
+	}
+
+	return value
+}
+
+// Returns text with the first letter of every word in upper case.
+func title(text string) string {
+	start := true
+
+	return strings.Map(func(char rune) rune {
+		if unicode.IsSpace(char) || char == '-' {
+			start = true
+			return char
+		}
+
+		if start {
+			start = false
+			return unicode.ToUpper(char)
+		}
+
+		return char
+	}, text)
+}
+
+// Indents every line of text with spaces spaces.
+func indent(spaces int, text string) string {
+	return markup.Indent(strings.TrimRight(text, "\n"), strings.Repeat(" ", spaces))
+}
+
+// Returns text cut to at most length characters, ending with "…" when it
+// was cut.
+func truncate(length int, text string) string {
+	if length <= 0 {
+		return ""
+	}
+
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+
+	runes := []rune(text)
+	return strings.TrimRightFunc(string(runes[:length-1]), unicode.IsSpace) + "…"
+}
@@ -0,0 +1,80 @@
+package templater
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/raphaeltannous/fem-helper/description"
+	"github.com/raphaeltannous/fem-helper/naming"
+)
+
+func TestTemplateFunctions(t *testing.T) {
+	markdown := MarkdownTemplater{naming: naming.DefaultScheme(), baseURL: DefaultBaseURL, descriptionFormat: description.Markdown}
+	markdown.naming.Padding = 2
+
+	functions := template.FuncMap{}
+	for name, function := range markdownTemplateFunctions {
+		functions[name] = function
+	}
+	for name, function := range markdown.functions() {
+		functions[name] = function
+	}
+
+	data := map[string]any{
+		"Published": time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+		"Duration":  time.Hour + 2*time.Minute + 3*time.Second,
+		"Tags":      []string{"go", "web"},
+		"Empty":     "",
+	}
+
+	functionTests := []struct {
+		text string
+		want string
+	}{
+		{`{{ slugify "Go & Friends!" }}`, "go-and-friends"},
+		{`{{ pad 3 }}`, "03"},
+		{`{{ pad 3 4 }}`, "0003"},
+		{`{{ duration .Duration }}`, "1h 2m 3s"},
+		{`{{ duration 90 }}`, "1m 30s"},
+		{`{{ duration 0 }}`, "0s"},
+		{`{{ .Published | date "2006-01-02" }}`, "2024-03-09"},
+		{`{{ timestamp 65 }}`, "01:05"},
+		{`{{ timestamp .Duration }}`, "1:02:03"},
+		{`{{ timestamp .Duration "iso" }}`, "PT1H2M3S"},
+		{`{{ timestamp 65 "seconds" }}`, "65"},
+		{`{{ wikilink "notes/a.md" "A" }}`, "[[notes/a.md|A]]"},
+		{`{{ wikilink "notes/a.md" }}`, "[[notes/a.md]]"},
+		{`{{ mdlink "A" "notes/a b.md" }}`, "[A](notes/a%20b.md)"},
+		{`{{ yaml "Vue: The Basics" }}`, `"Vue: The Basics"`},
+		{`{{ join .Tags ", " }}`, "go, web"},
+		{`{{ .Empty | default "none" }}`, "none"},
+		{`{{ .Tags | default "none" }}`, "[go web]"},
+		{`{{ upper "go" }} {{ lower "GO" }} {{ title "go in practice" }}`, "GO go Go In Practice"},
+		{`{{ indent 2 "a\nb" }}`, "  a\n  b"},
+		{`{{ truncate 8 "Introduction to Go" }}`, "Introdu…"},
+		{`{{ truncate 8 "Go" }}`, "Go"},
+		{`{{ description "<p>Use <em>Go</em></p><a href=\"/courses/\">all</a>" }}`, "Use *Go*\n\n[all](https://frontendmasters.com/courses/)"},
+		{`{{ description "<p>Use <em>Go</em></p>" "org" }}`, "Use /Go/"},
+		{`{{ description "<p>Use <em>Go</em></p>" "text" }}`, "Use Go"},
+	}
+
+	for _, c := range functionTests {
+		t.Run(c.text, func(t *testing.T) {
+			tmpl, err := template.New("test").Funcs(functions).Parse(c.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result strings.Builder
+			if err := tmpl.Execute(&result, data); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := result.String(); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
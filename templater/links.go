@@ -0,0 +1,66 @@
+package templater
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/raphaeltannous/fem-helper/api"
+)
+
+// Site the player URLs point to by default.
+const DefaultBaseURL = "https://frontendmasters.com/"
+
+// Returns baseURL checked and ending with a slash, or DefaultBaseURL if it
+// is empty.
+func normalizeBaseURL(baseURL string) (string, error) {
+	if baseURL == "" {
+		return DefaultBaseURL, nil
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q, expected an absolute URL such as %s", baseURL, DefaultBaseURL)
+	}
+
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	return baseURL, nil
+}
+
+// Returns the player URL of the course.
+func (markdown MarkdownTemplater) courseURL() string {
+	return markdown.baseURL + "courses/" + url.PathEscape(markdown.course.Slug) + "/"
+}
+
+// Returns the player URL of lesson.
+func (markdown MarkdownTemplater) lessonURL(lesson api.LessonData) string {
+	return markdown.courseURL() + url.PathEscape(lesson.Slug) + "/"
+}
+
+// Returns the URL that seeks the player at lessonURL to the start of
+// anno, or an empty string if either is unknown.
+func annotationURL(lessonURL string, anno api.AnnotationData) string {
+	if lessonURL == "" || len(anno.Range) == 0 || anno.Range[0] < 0 {
+		return ""
+	}
+
+	return seekURL(lessonURL, anno.Range[0])
+}
+
+// Returns lessonURL with its t query parameter set to seconds.
+func seekURL(lessonURL string, seconds int) string {
+	parsed, err := url.Parse(lessonURL)
+	if err != nil {
+		return lessonURL
+	}
+
+	query := parsed.Query()
+	query.Set("t", strconv.Itoa(seconds))
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
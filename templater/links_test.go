@@ -0,0 +1,91 @@
+package templater
+
+import (
+	"testing"
+
+	"github.com/raphaeltannous/fem-helper/api"
+)
+
+func TestAnnotationURL(t *testing.T) {
+	const lessonURL = "https://frontendmasters.com/courses/go-basics/setup/"
+
+	urlTests := []struct {
+		name      string
+		lessonURL string
+		anno      api.AnnotationData
+		want      string
+	}{
+		{"range", lessonURL, api.AnnotationData{Range: []int{65, 70}}, lessonURL + "?t=65"},
+		{"single", lessonURL, api.AnnotationData{Range: []int{3601}}, lessonURL + "?t=3601"},
+		{"empty range", lessonURL, api.AnnotationData{}, ""},
+		{"negative", lessonURL, api.AnnotationData{Range: []int{-5, 10}}, ""},
+		{"no lesson URL", "", api.AnnotationData{Range: []int{65, 70}}, ""},
+		{"query", lessonURL + "?a=b", api.AnnotationData{Range: []int{1}}, lessonURL + "?a=b&t=1"},
+	}
+
+	for _, c := range urlTests {
+		t.Run(c.name, func(t *testing.T) {
+			if got := annotationURL(c.lessonURL, c.anno); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	baseURLTests := []struct {
+		baseURL string
+		want    string
+		err     bool
+	}{
+		{"", DefaultBaseURL, false},
+		{"http://localhost:8080", "http://localhost:8080/", false},
+		{"https://example.com/fem/", "https://example.com/fem/", false},
+		{"localhost:8080", "", true},
+		{"/courses", "", true},
+	}
+
+	for _, c := range baseURLTests {
+		t.Run(c.baseURL, func(t *testing.T) {
+			got, err := normalizeBaseURL(c.baseURL)
+			if (err != nil) != c.err {
+				t.Fatalf("got error %v, want error %v", err, c.err)
+			}
+
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFormatAnnotationsToMarkdown(t *testing.T) {
+	annos := api.Annotations{{Range: []int{65, 70}, Message: "Install Go first."}}
+	markdown := MarkdownTemplater{annotations: DefaultAnnotationOptions()}
+
+	formatTests := []struct {
+		name        string
+		annotations any
+		want        string
+	}{
+		{"annotations", annos, "\n> [!NOTE]+ 01:05 -> 01:10\n> Install Go first.\n"},
+		{"linked", markdown.annotationsContext(annos, "https://example.com/setup/"), "\n> [!NOTE]+ [01:05 -> 01:10](https://example.com/setup/?t=65)\n> Install Go first.\n"},
+	}
+
+	for _, c := range formatTests {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := formatAnnotationsToMarkdown(c.annotations)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+
+	if _, err := formatAnnotationsToMarkdown("notes"); err == nil {
+		t.Error("got no error for a string")
+	}
+}
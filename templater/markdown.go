@@ -1,148 +1,401 @@
 package templater
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	"embed"
 	"fmt"
+	"maps"
 	"os"
-	"path/filepath"
+	"path"
+	"slices"
 	"strings"
 	"text/template"
 
 	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/description"
+	"github.com/raphaeltannous/fem-helper/naming"
 	"github.com/raphaeltannous/fem-helper/outputdir"
+	"github.com/raphaeltannous/fem-helper/yaml"
 )
 
 //go:embed templates
 var templatesFolder embed.FS
 
+// Directory of the default templates in templatesFolder, with one folder
+// per flavor.
+const defaultTemplatesDir = "templates"
+
+// Flavor of the default templates used unless Options.Flavor is set.
+const DefaultFlavor = "obsidian"
+
+// Flavors of default templates, named after the note-taking app they are
+// written for.
+var Flavors = []string{DefaultFlavor}
+
+// Format the description function writes in by default, by flavor.
+var flavorDescriptionFormats = map[string]description.Format{
+	DefaultFlavor: description.Markdown,
+}
+
+const (
+	CourseTemplateName     = "course.tmpl"
+	LessonTemplateName     = "lesson.tmpl"
+	SectionTemplateName    = "section.tmpl"
+	AnnotationTemplateName = "annotation.tmpl"
+)
+
+// Names of the templates that can be replaced with custom templates.
+// annotation.tmpl only holds partials, it is parsed along every other
+// template.
+var TemplateNames = []string{
+	CourseTemplateName,
+	LessonTemplateName,
+	SectionTemplateName,
+	AnnotationTemplateName,
+}
+
 type MarkdownTemplater struct {
 	course struct {
 		api.CourseData
 		Tags []string
 	}
-	outputDirectory outputdir.OutputDirectory
-
-	courseTemplate *template.Template
-	lessonTemplate *template.Template
+	output       outputdir.FS
+	naming       naming.Scheme
+	layout       naming.Layout
+	sectionNotes bool
+	// Sections, and section notes, whose paths are not safe to write.
+	// Skipped sections have no lessons left.
+	skippedSections     map[int]bool
+	skippedSectionNotes map[int]bool
+	annotations         AnnotationOptions
+	// Extra frontmatter keys of every note.
+	extraFrontmatter yaml.Map
+	tagPrefix        string
+	sectionTags      bool
+	progressView     string
+	progressFile     string
+	// Site of the player URLs, ending with a slash.
+	baseURL string
+	// Format descriptions are written in unless a template picks one.
+	descriptionFormat description.Format
+	warn              func(error)
+
+	courseTemplate  *template.Template
+	lessonTemplate  *template.Template
+	sectionTemplate *template.Template
 }
 
-var markdownTemplateFunctions = template.FuncMap{
-	"formattags":        formatTagsToMarkdown,
-	"formatcoursedata":  formatCourseDataToMarkdown,
-	"formatannotations": formatAnnotationsToMarkdown,
+// Options configures a MarkdownTemplater.
+type Options struct {
+	// Tags of every note. They are normalized with tag.Normalize.
+	Tags []string
+	// Prefix of the course tag, DefaultTagPrefix by default. The course
+	// tag is <TagPrefix>/<course slug>, or the course slug if TagPrefix is
+	// empty.
+	TagPrefix string
+	// Tag lessons and section notes with <course tag>/<section slug>
+	// instead of the course tag.
+	SectionTags bool
+
+	// Paths of custom template files by template name, see TemplateNames.
+	// The embedded default of Flavor is used for every template without
+	// one.
+	CustomTemplates map[string]string
+	// One of Flavors, DefaultFlavor if empty.
+	Flavor string
+
+	// Render a section note into each section folder. Always enabled when
+	// a custom section.tmpl is given.
+	SectionNotes bool
+
+	Naming naming.Scheme
+
+	Annotations AnnotationOptions
+
+	// Site the course, lesson and annotation URLs point to,
+	// DefaultBaseURL if empty.
+	BaseURL string
+
+	// Kind of progress view generated next to the course note, one of
+	// ProgressViews, or empty for none.
+	ProgressView string
+
+	// Extra frontmatter keys added to every note. They replace the keys
+	// of the default frontmatter they share.
+	Frontmatter yaml.Map
+
+	// Called for every problem that does not stop the generation, such as
+	// lessons that are skipped because their path is not safe to write.
+	Warn func(error)
 }
 
-func NewMarkdownTemplater(course api.CourseData, outputDirector outputdir.OutputDirectory, tags []string, courseTemplate, lessonTemplate string) MarkdownTemplater {
+func NewMarkdownTemplater(course api.CourseData, output outputdir.FS, options Options) (MarkdownTemplater, error) {
 	markdownTemp := MarkdownTemplater{
 		course: struct {
 			api.CourseData
 			Tags []string
 		}{
-			course, tags,
+			course, options.Tags,
 		},
-		outputDirectory: outputDirector,
-	}
-
-	markdownTemp.courseTemplate = template.Must(
-		template.New(
-			filepath.Base(courseTemplate),
-		).Funcs(
-			markdownTemplateFunctions,
-		).ParseFS(
-			templatesFolder,
-			courseTemplate,
-		),
-	)
+		output:           output,
+		naming:           options.Naming,
+		sectionNotes:     options.SectionNotes || options.CustomTemplates[SectionTemplateName] != "",
+		annotations:      options.Annotations,
+		extraFrontmatter: options.Frontmatter,
+		sectionTags:      options.SectionTags,
+		progressView:     options.ProgressView,
+		warn:             options.Warn,
+	}
+	if markdownTemp.warn == nil {
+		markdownTemp.warn = func(error) {}
+	}
+
+	if err := markdownTemp.normalizeTags(options.Tags, options.TagPrefix); err != nil {
+		return MarkdownTemplater{}, err
+	}
+
+	baseURL, err := normalizeBaseURL(options.BaseURL)
+	if err != nil {
+		return MarkdownTemplater{}, err
+	}
+	markdownTemp.baseURL = baseURL
+
+	if err := options.Annotations.validate(); err != nil {
+		return MarkdownTemplater{}, err
+	}
+
+	layout, err := options.Naming.Layout(course)
+	if err != nil {
+		return MarkdownTemplater{}, err
+	}
+	markdownTemp.layout = layout
+	markdownTemp.skipInvalidPaths()
+
+	if options.ProgressView != "" {
+		if !slices.Contains(ProgressViews, options.ProgressView) {
+			return MarkdownTemplater{}, fmt.Errorf("unknown progress view %q, expected one of: %s", options.ProgressView, strings.Join(ProgressViews, ", "))
+		}
+		markdownTemp.progressFile = progressViewPath(layout, options.ProgressView)
+	}
+
+	flavor := cmp.Or(options.Flavor, DefaultFlavor)
+	if !slices.Contains(Flavors, flavor) {
+		return MarkdownTemplater{}, fmt.Errorf("unknown template flavor %q, expected one of: %s", flavor, strings.Join(Flavors, ", "))
+	}
+	markdownTemp.descriptionFormat = flavorDescriptionFormats[flavor]
 
-	markdownTemp.lessonTemplate = template.Must(
-		template.New(
-			filepath.Base(lessonTemplate),
-		).Funcs(
-			markdownTemplateFunctions,
-		).ParseFS(
-			templatesFolder,
-			lessonTemplate,
-		),
+	functions := maps.Clone(markdownTemplateFunctions)
+	maps.Copy(functions, markdownTemp.functions())
+
+	partials, err := readTemplate(flavor, AnnotationTemplateName, options.CustomTemplates[AnnotationTemplateName])
+	if err != nil {
+		return MarkdownTemplater{}, err
+	}
+
+	templates := map[string]**template.Template{
+		CourseTemplateName:  &markdownTemp.courseTemplate,
+		LessonTemplateName:  &markdownTemp.lessonTemplate,
+		SectionTemplateName: &markdownTemp.sectionTemplate,
+	}
+	for name, tmpl := range templates {
+		text, err := readTemplate(flavor, name, options.CustomTemplates[name])
+		if err != nil {
+			return MarkdownTemplater{}, err
+		}
+
+		// The partials are parsed first, so that templates can redefine them.
+		*tmpl, err = template.New(name).Funcs(functions).Parse(partials)
+		if err != nil {
+			return MarkdownTemplater{}, fmt.Errorf("%s: %w", AnnotationTemplateName, err)
+		}
+
+		if _, err := (*tmpl).Parse(text); err != nil {
+			return MarkdownTemplater{}, err
+		}
+	}
+
+	return markdownTemp, nil
+}
+
+// Returns the text of the template name from customPath, or from the
+// embedded defaults of flavor if customPath is empty.
+func readTemplate(flavor, name, customPath string) (string, error) {
+	var (
+		text []byte
+		err  error
 	)
+	if customPath != "" {
+		text, err = os.ReadFile(customPath)
+	} else {
+		text, err = FlavorTemplate(flavor, name)
+	}
+
+	return string(text), err
+}
+
+// Returns the content of the embedded default template name.
+func DefaultTemplate(name string) ([]byte, error) {
+	return FlavorTemplate(DefaultFlavor, name)
+}
 
-	return markdownTemp
+// Returns the content of the embedded template name of flavor.
+func FlavorTemplate(flavor, name string) ([]byte, error) {
+	return templatesFolder.ReadFile(path.Join(defaultTemplatesDir, flavor, name))
 }
 
-func (markdown MarkdownTemplater) GenerateCourseMarkdown() error {
+// Generates the course and all of its lessons into the output FS.
+// Everything is committed only once every note was generated, so a failure
+// or a cancelled ctx leaves the output as it was.
+func (markdown MarkdownTemplater) GenerateCourseMarkdown(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			markdown.output.Rollback()
+		}
+	}()
+
 	if err := markdown.GenerateCourseFromTemplate(); err != nil {
 		return err
 	}
 
-	for x, section := range markdown.course.Sections {
-		sectionDir, err := markdown.outputDirectory.Create(
-			fmt.Sprintf("%d-%s", x, section.SlugifiedSectionTitle()),
-		)
-		if err != nil {
+	if markdown.progressFile != "" {
+		if err := markdown.output.WriteFile(markdown.progressFile, markdown.formatProgressView()); err != nil {
 			return err
 		}
+	}
 
-		for _, lessonIndex := range section.LessonsIndex {
-			lessonHash := markdown.course.LessonsHash[lessonIndex]
-			lesson := markdown.course.Lessons[lessonHash]
+	for position, lesson := range markdown.course.All() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-			if err := markdown.GenerateLessonFromTemplate(sectionDir, lesson); err != nil {
+		if sectionDir := markdown.layout.SectionDirs[position.SectionIndex]; position.FirstInSection() && sectionDir != "" {
+			if err := markdown.output.MkdirAll(sectionDir); err != nil {
 				return err
 			}
 		}
+
+		if err := markdown.GenerateLessonFromTemplate(position, lesson); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
+	for x := range markdown.course.Sections {
+		if !markdown.hasSectionNote(x) {
+			continue
+		}
 
-func (markdown MarkdownTemplater) GenerateCourseFromTemplate() error {
-	outputFile := filepath.Join(
-		markdown.outputDirectory.String(),
-		fmt.Sprintf("%s.md", markdown.course.Slug),
-	)
+		if err := markdown.GenerateSectionFromTemplate(x); err != nil {
+			return err
+		}
+	}
 
-	file, err := os.Create(outputFile)
-	if err != nil {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	return markdown.courseTemplate.Execute(file, markdown.course)
+	return markdown.output.Commit()
 }
 
-func (markdown MarkdownTemplater) GenerateLessonFromTemplate(outputDirectory outputdir.OutputDirectory, lesson api.LessonData) error {
-	outputFile := filepath.Join(
-		outputDirectory.String(),
-		fmt.Sprintf("%d-%s.md", lesson.Index, lesson.Slug),
-	)
+// Leaves the sections, lessons and section notes whose paths are not
+// safe to write out of the course, reporting each of them, so that no
+// note links to a note that is not written.
+func (markdown *MarkdownTemplater) skipInvalidPaths() {
+	course := &markdown.course.CourseData
+	course.Sections = slices.Clone(course.Sections)
+	markdown.skippedSections = make(map[int]bool)
+	markdown.skippedSectionNotes = make(map[int]bool)
 
-	file, err := os.Create(outputFile)
-	if err != nil {
+	for x, section := range course.Sections {
+		if sectionDir := markdown.layout.SectionDirs[x]; sectionDir != "" {
+			if err := outputdir.ValidatePath(sectionDir); err != nil {
+				markdown.warn(fmt.Errorf("skipping section %d (%s) and its lessons: %w", x, section.Title, err))
+				markdown.skippedSections[x] = true
+				course.Sections[x].LessonsIndex = nil
+				continue
+			}
+		}
+
+		if markdown.sectionNotes {
+			if err := outputdir.ValidatePath(markdown.layout.SectionFiles[x]); err != nil {
+				markdown.warn(fmt.Errorf("skipping section note %d (%s): %w", x, section.Title, err))
+				markdown.skippedSectionNotes[x] = true
+			}
+		}
+
+		course.Sections[x].LessonsIndex = slices.DeleteFunc(slices.Clone(section.LessonsIndex), func(index int) bool {
+			lessonFile, ok := markdown.layout.LessonFiles[index]
+			if !ok {
+				return false
+			}
+
+			err := outputdir.ValidatePath(lessonFile)
+			if err != nil {
+				markdown.warn(fmt.Errorf("skipping lesson %d: %w", index, err))
+			}
+			return err != nil
+		})
+	}
+}
+
+// Returns true if the section at index x gets a section note.
+func (markdown MarkdownTemplater) hasSectionNote(x int) bool {
+	return markdown.sectionNotes && !markdown.skippedSections[x] && !markdown.skippedSectionNotes[x]
+}
+
+func (markdown MarkdownTemplater) GenerateCourseFromTemplate() error {
+	var output bytes.Buffer
+	if err := markdown.courseTemplate.Execute(&output, markdown.courseNoteContext()); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	return markdown.lessonTemplate.Execute(file, struct {
-		api.LessonData
-		Tags       []string
-		CourseSlug string
-	}{lesson, markdown.course.Tags, markdown.course.Slug})
+	return markdown.output.WriteFile(markdown.layout.CourseFile, output.Bytes())
+}
+
+func (markdown MarkdownTemplater) GenerateLessonFromTemplate(position api.Position, lesson api.LessonData) error {
+	var output bytes.Buffer
+	err := markdown.lessonTemplate.Execute(&output, markdown.lessonContext(position, lesson))
+	if err != nil {
+		return fmt.Errorf("lesson %d (%s): %w", lesson.Index, lesson.Slug, err)
+	}
+
+	return markdown.output.WriteFile(markdown.layout.LessonFiles[lesson.Index], output.Bytes())
 }
 
-func formatCourseDataToMarkdown(course api.CourseData) string {
+// Generates the note of the section at index x of the course sections.
+func (markdown MarkdownTemplater) GenerateSectionFromTemplate(x int) error {
+	var output bytes.Buffer
+	if err := markdown.sectionTemplate.Execute(&output, markdown.sectionNoteContext(x)); err != nil {
+		return fmt.Errorf("section %d (%s): %w", x, markdown.course.Sections[x].Title, err)
+	}
+
+	return markdown.output.WriteFile(markdown.layout.SectionFiles[x], output.Bytes())
+}
+
+// Returns the course index, linking to every lesson, and section note if
+// they are generated, with the same paths they are written to.
+func (markdown MarkdownTemplater) formatCourseDataToMarkdown(course api.CourseData) string {
 	var result strings.Builder
 
 	for x, section := range course.Sections {
-		result.WriteString(
-			fmt.Sprintf("%d. %s\n", x, section.Title),
-		)
+		if markdown.skippedSections[x] {
+			continue
+		}
 
-		sectionSlug := section.SlugifiedSectionTitle()
-		for _, lessonIndex := range section.LessonsIndex {
-			lessonHash := course.LessonsHash[lessonIndex]
-			lesson := course.Lessons[lessonHash]
+		if markdown.hasSectionNote(x) {
+			result.WriteString(
+				fmt.Sprintf("%d. %s\n", markdown.naming.Number(x), wikilink(markdown.layout.SectionFiles[x], section.Title)),
+			)
+		} else {
+			result.WriteString(
+				fmt.Sprintf("%d. %s\n", markdown.naming.Number(x), escapeInline(section.Title)),
+			)
+		}
 
+		for _, lesson := range course.Section(x) {
 			result.WriteString(
-				fmt.Sprintf("  - [[%d-%s/%d-%s.md|%d. %s]]\n", x, sectionSlug, lessonIndex, lesson.Slug, lessonIndex, lesson.Title),
+				fmt.Sprintf("  - %s\n", wikilink(markdown.layout.LessonFiles[lesson.Index], fmt.Sprintf("%d. %s", markdown.naming.Number(lesson.Index), lesson.Title))),
 			)
 		}
 	}
@@ -162,16 +415,3 @@ func formatTagsToMarkdown(tags []string) string {
 
 	return result.String()
 }
-
-func formatAnnotationsToMarkdown(annos api.Annotations) string {
-	var result strings.Builder
-
-	for _, anno := range annos {
-		result.WriteString(
-			fmt.Sprintf("\n> [!NOTE]+ %s\n", strings.Join(anno.GetReadableRange(), " -> ")),
-		)
-		result.WriteString(fmt.Sprintf("> %s\n", anno.Message))
-	}
-
-	return result.String()
-}
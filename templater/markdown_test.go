@@ -0,0 +1,580 @@
+package templater
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/naming"
+	"github.com/raphaeltannous/fem-helper/outputdir"
+	"github.com/raphaeltannous/fem-helper/yaml"
+)
+
+func testCourse() api.CourseData {
+	return api.CourseData{
+		Slug:        "go-basics",
+		Title:       "Go Basics",
+		Description: "Learn <strong>Go</strong>.",
+		LessonsHash: map[int]string{0: "a", 1: "b", 2: "c"},
+		Sections: api.Sections{
+			{Title: "Introduction", Duration: 5*time.Minute + 10*time.Second, LessonsIndex: []int{0, 1}},
+			{Title: "Wrapping Up", Duration: 2 * time.Minute, LessonsIndex: []int{2}},
+		},
+		Lessons: map[string]api.LessonData{
+			"a": {Slug: "introduction", Title: "Introduction", Index: 0},
+			"b": {Slug: "setup", Title: "Setup", Description: "<p>Install <code>go</code> &amp; an editor.</p>", Index: 1, Annotations: api.Annotations{
+				{Range: []int{65, 70}, Message: "Install Go first."},
+			}},
+			"c": {Slug: "wrapping-up", Title: "Wrapping Up", Index: 2},
+		},
+	}
+}
+
+func testOptions(tags []string) Options {
+	return Options{
+		Tags:        tags,
+		TagPrefix:   DefaultTagPrefix,
+		Naming:      naming.DefaultScheme(),
+		Annotations: DefaultAnnotationOptions(),
+	}
+}
+
+func TestMarkdownTemplater_GenerateCourseMarkdown(t *testing.T) {
+	memFS := outputdir.NewMemFS()
+	markdown, err := NewMarkdownTemplater(testCourse(), memFS, testOptions([]string{"go"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNames := []string{
+		"0-introduction/0-introduction.md",
+		"0-introduction/1-setup.md",
+		"1-wrapping-up/2-wrapping-up.md",
+		"go-basics.md",
+	}
+	if names := memFS.Names(); !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("got %v, want %v", names, wantNames)
+	}
+
+	contentTests := []struct {
+		name string
+		want string
+	}{
+		{"go-basics.md", "  - [[0-introduction/1-setup.md|1. Setup]]\n"},
+		{"go-basics.md", "  - frontend-masters/go-basics\n  - go\n"},
+		{"go-basics.md", "# Go Basics\n\nLearn **Go**.\n\n0. Introduction\n"},
+		{"0-introduction/1-setup.md", "> [!NOTE]+ [01:05 -> 01:10](https://frontendmasters.com/courses/go-basics/setup/?t=65)\n> Install Go first.\n"},
+		{"0-introduction/1-setup.md", "[Watch](https://frontendmasters.com/courses/go-basics/setup/)\n\nInstall `go` & an editor.\n\n## Annotations\n"},
+		{"0-introduction/1-setup.md", "[[go-basics.md|Go Basics]] › 0. Introduction · Lesson 2 of 3 · [Watch](https://frontendmasters.com/courses/go-basics/setup/)\n"},
+		{"0-introduction/1-setup.md", "Previous: [[0-introduction/0-introduction.md|0. Introduction]] · Next: [[1-wrapping-up/2-wrapping-up.md|2. Wrapping Up]]\n"},
+	}
+
+	for _, c := range contentTests {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := memFS.ReadFile(c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), c.want) {
+				t.Errorf("%s does not contain %q:\n%s", c.name, c.want, data)
+			}
+		})
+	}
+}
+
+func TestMarkdownTemplater_GenerateCourseMarkdownCancelled(t *testing.T) {
+	memFS := outputdir.NewMemFS()
+	markdown, err := NewMarkdownTemplater(testCourse(), memFS, testOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := markdown.GenerateCourseMarkdown(ctx); err != context.Canceled {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+
+	if names := memFS.Names(); len(names) != 0 {
+		t.Errorf("got %v after cancellation, want no files", names)
+	}
+}
+
+func TestMarkdownTemplater_GenerateCourseMarkdownInvalidPath(t *testing.T) {
+	course := testCourse()
+	lesson := course.Lessons["b"]
+	lesson.Title = "../../etc/passwd"
+	course.Lessons["b"] = lesson
+
+	var warnings []error
+	options := testOptions(nil)
+	options.Naming.LessonName = "{{.Index}}-{{.Title}}"
+	options.Warn = func(err error) {
+		warnings = append(warnings, err)
+	}
+
+	memFS := outputdir.NewMemFS()
+	markdown, err := NewMarkdownTemplater(course, memFS, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNames := []string{
+		"0-introduction/0-Introduction.md",
+		"1-wrapping-up/2-Wrapping Up.md",
+		"go-basics.md",
+	}
+	if names := memFS.Names(); !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("got %v, want %v", names, wantNames)
+	}
+
+	var invalidPath *outputdir.InvalidPathError
+	if len(warnings) != 1 || !errors.As(warnings[0], &invalidPath) {
+		t.Errorf("got warnings %v, want one InvalidPathError", warnings)
+	}
+
+	// The skipped lesson is not linked from the other notes.
+	for _, name := range wantNames {
+		data, err := memFS.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(data), "passwd") {
+			t.Errorf("%s links to the skipped lesson:\n%s", name, data)
+		}
+	}
+
+	data, _ := memFS.ReadFile("go-basics.md")
+	if want := "lesson_count: 2\n"; !strings.Contains(string(data), want) {
+		t.Errorf("does not contain %q:\n%s", want, data)
+	}
+
+	data, _ = memFS.ReadFile("0-introduction/0-Introduction.md")
+	if want := "Next: [[1-wrapping-up/2-Wrapping Up.md|2. Wrapping Up]]"; !strings.Contains(string(data), want) {
+		t.Errorf("does not contain %q:\n%s", want, data)
+	}
+}
+
+func TestMarkdownTemplater_GenerateCourseMarkdownSectionNotes(t *testing.T) {
+	options := testOptions(nil)
+	options.SectionNotes = true
+
+	memFS := outputdir.NewMemFS()
+	markdown, err := NewMarkdownTemplater(testCourse(), memFS, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	contentTests := []struct {
+		name string
+		want string
+	}{
+		// The first lesson keeps its name, which the section note would
+		// have had.
+		{"go-basics.md", "0. [[0-introduction/0-introduction-2.md|Introduction]]\n  - [[0-introduction/0-introduction.md|0. Introduction]]\n"},
+		{"0-introduction/0-introduction-2.md", "[[go-basics.md|Go Basics]] · 5m10s\n"},
+		{"0-introduction/0-introduction-2.md", "## Lessons\n\n- [[0-introduction/0-introduction.md|0. Introduction]]\n- [[0-introduction/1-setup.md|1. Setup]]\n"},
+		{"0-introduction/0-introduction-2.md", "### [[0-introduction/1-setup.md|1. Setup]]\n\n> [!NOTE]+ [01:05 -> 01:10](https://frontendmasters.com/courses/go-basics/setup/?t=65)\n"},
+		{"0-introduction/1-setup.md", "› [[0-introduction/0-introduction-2.md|0. Introduction]] ·"},
+		{"1-wrapping-up/1-wrapping-up.md", "- [[1-wrapping-up/2-wrapping-up.md|2. Wrapping Up]]\n"},
+	}
+
+	for _, c := range contentTests {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := memFS.ReadFile(c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), c.want) {
+				t.Errorf("%s does not contain %q:\n%s", c.name, c.want, data)
+			}
+		})
+	}
+}
+
+func TestMarkdownTemplater_AnnotationStyles(t *testing.T) {
+	course := testCourse()
+	lesson := course.Lessons["b"]
+	lesson.Annotations = api.Annotations{
+		{Range: []int{65, 70}, Message: "Install Go first.\n\nThen run | go version."},
+	}
+	course.Lessons["b"] = lesson
+
+	styleTests := []struct {
+		name    string
+		options AnnotationOptions
+		want    string
+	}{
+		{"callout", AnnotationOptions{Timestamps: api.TimestampClock, Style: "callout", Callout: "TIP", Fold: "-"}, "> [!TIP]- [01:05 -> 01:10](http://localhost:8080/courses/go-basics/setup/?t=65)\n> Install Go first.\n>\n> Then run | go version.\n"},
+		{"callout not foldable", AnnotationOptions{Timestamps: api.TimestampClock, Style: "callout", Callout: "NOTE"}, "> [!NOTE] [01:05 -> 01:10](http://localhost:8080/courses/go-basics/setup/?t=65)\n"},
+		{"list", AnnotationOptions{Timestamps: api.TimestampClock, Style: "list"}, "- [01:05 -> 01:10](http://localhost:8080/courses/go-basics/setup/?t=65)\n  Install Go first.\n\n  Then run | go version.\n"},
+		{"table", AnnotationOptions{Timestamps: api.TimestampClock, Style: "table"}, "| Time | Note |\n| --- | --- |\n| [01:05 -> 01:10](http://localhost:8080/courses/go-basics/setup/?t=65) | Install Go first.<br><br>Then run \\| go version. |\n"},
+		{"iso timestamps", AnnotationOptions{Timestamps: api.TimestampISO, Style: "list"}, "- [PT1M5S -> PT1M10S](http://localhost:8080/courses/go-basics/setup/?t=65)\n"},
+		{"timeline", AnnotationOptions{Timestamps: api.TimestampClock, Style: "timeline"}, "- **[01:05](http://localhost:8080/courses/go-basics/setup/?t=65)**\n  Install Go first.\n"},
+	}
+
+	for _, c := range styleTests {
+		t.Run(c.name, func(t *testing.T) {
+			options := testOptions(nil)
+			options.Annotations = c.options
+			options.BaseURL = "http://localhost:8080"
+
+			memFS := outputdir.NewMemFS()
+			markdown, err := NewMarkdownTemplater(course, memFS, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := memFS.ReadFile("0-introduction/1-setup.md")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), c.want) {
+				t.Errorf("does not contain %q:\n%s", c.want, data)
+			}
+		})
+	}
+
+	t.Run("unknown style", func(t *testing.T) {
+		options := testOptions(nil)
+		options.Annotations.Style = "poem"
+
+		if _, err := NewMarkdownTemplater(course, outputdir.NewMemFS(), options); err == nil {
+			t.Error("got no error for an unknown style")
+		}
+	})
+}
+
+func TestMarkdownTemplater_ProcessedAnnotations(t *testing.T) {
+	course := testCourse()
+	lesson := course.Lessons["b"]
+	lesson.Annotations = api.Annotations{
+		{Range: []int{80, 90}, Message: "Careful, `go get` is deprecated here."},
+		{Range: []int{65, 70}, Message: "Docs at https://go.dev/doc."},
+		{Range: []int{80, 90}, Message: "Careful, `go get` is deprecated here."},
+		{Range: []int{68, 75}, Message: "Tip: use go install."},
+	}
+	course.Lessons["b"] = lesson
+
+	processTests := []struct {
+		name    string
+		options AnnotationOptions
+		want    string
+	}{
+		{
+			name:    "sorted without duplicates",
+			options: AnnotationOptions{Timestamps: api.TimestampClock, Style: "callout", Callout: "NOTE"},
+			want:    "> [!NOTE] [01:05 -> 01:10](https://frontendmasters.com/courses/go-basics/setup/?t=65)\n> Docs at https://go.dev/doc.\n\n> [!NOTE] [01:08 -> 01:15](https://frontendmasters.com/courses/go-basics/setup/?t=68)\n> Tip: use go install.\n\n> [!NOTE] [01:20 -> 01:30](https://frontendmasters.com/courses/go-basics/setup/?t=80)\n> Careful, `go get` is deprecated here.\n\n",
+		},
+		{
+			name:    "merged",
+			options: AnnotationOptions{Timestamps: api.TimestampClock, Style: "list", Merge: true},
+			want:    "- [01:05 -> 01:15](https://frontendmasters.com/courses/go-basics/setup/?t=65)\n  Docs at https://go.dev/doc.\n\n  Tip: use go install.\n- [01:20 -> 01:30](https://frontendmasters.com/courses/go-basics/setup/?t=80)\n",
+		},
+		{
+			name:    "classified",
+			options: AnnotationOptions{Timestamps: api.TimestampClock, Style: "callout", Callout: "NOTE", Classify: true},
+			want:    "> [!INFO] [01:05 -> 01:10](https://frontendmasters.com/courses/go-basics/setup/?t=65)\n> Docs at https://go.dev/doc.\n\n> [!TIP] [01:08 -> 01:15](https://frontendmasters.com/courses/go-basics/setup/?t=68)\n> Tip: use go install.\n\n> [!WARNING] [01:20 -> 01:30](https://frontendmasters.com/courses/go-basics/setup/?t=80)\n",
+		},
+	}
+
+	for _, c := range processTests {
+		t.Run(c.name, func(t *testing.T) {
+			options := testOptions(nil)
+			options.Annotations = c.options
+
+			memFS := outputdir.NewMemFS()
+			markdown, err := NewMarkdownTemplater(course, memFS, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := memFS.ReadFile("0-introduction/1-setup.md")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), c.want) {
+				t.Errorf("does not contain %q:\n%s", c.want, data)
+			}
+		})
+	}
+}
+
+func TestMarkdownTemplater_Frontmatter(t *testing.T) {
+	course := testCourse()
+	course.Title = `Go: The "Basics"`
+	lesson := course.Lessons["b"]
+	lesson.Title = "Setup #1"
+	lesson.Duration = 4*time.Minute + 40*time.Second
+	course.Lessons["b"] = lesson
+	course.Published = time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	options := testOptions([]string{"#go"})
+	options.Frontmatter = yaml.Map{
+		{Key: "status", Value: "to do: soon"},
+		{Key: "aliases", Value: []string{}},
+	}
+
+	memFS := outputdir.NewMemFS()
+	markdown, err := NewMarkdownTemplater(course, memFS, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	frontmatterTests := []struct {
+		name string
+		want string
+	}{
+		{"go-basics.md", "---\naliases: []\ntags:\n  - frontend-masters/go-basics\n  - go\nlesson_count: 3\nduration_minutes: 7\npublished: 2024-03-09\nfem_url: https://frontendmasters.com/courses/go-basics/\nstatus: \"to do: soon\"\n---\n\n# Go: The \"Basics\"\n"},
+		{"0-introduction/1-setup.md", "---\naliases: []\ntags:\n  - frontend-masters/go-basics\n  - go\ncourse: \"[[go-basics.md|Go: The \\\"Basics\\\"]]\"\nsection: Introduction\nsection_index: 0\nlesson_index: 1\nduration_minutes: 5\npublished: 2024-03-09\nfem_url: https://frontendmasters.com/courses/go-basics/setup/\nstatus: \"to do: soon\"\nannotation_count: 1\n---\n\n# 1. Setup \\#1\n"},
+	}
+
+	for _, c := range frontmatterTests {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := memFS.ReadFile(c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(string(data), c.want) {
+				t.Errorf("%s does not start with %q:\n%s", c.name, c.want, data)
+			}
+		})
+	}
+
+	options.Frontmatter = nil
+	markdown, err = NewMarkdownTemplater(course, outputdir.NewMemFS(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := yaml.Map{
+		{Key: "aliases", Value: []string{"1. Setup #1"}},
+		{Key: "tags", Value: []string{"frontend-masters/go-basics", "go"}},
+		{Key: "course", Value: `[[go-basics.md|Go: The "Basics"]]`},
+		{Key: "section", Value: "Introduction"},
+		{Key: "section_index", Value: 0},
+		{Key: "lesson_index", Value: 1},
+		{Key: "duration_minutes", Value: 5},
+		{Key: "published", Value: course.Published},
+		{Key: "fem_url", Value: "https://frontendmasters.com/courses/go-basics/setup/"},
+		{Key: "status", Value: "todo"},
+		{Key: "annotation_count", Value: 1},
+	}
+	position, _ := course.PositionOf(1)
+	if got := markdown.lessonContext(position, lesson).Frontmatter; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMarkdownTemplater_Tags(t *testing.T) {
+	tagTests := []struct {
+		name        string
+		prefix      string
+		sectionTags bool
+		want        []string
+	}{
+		{"default", DefaultTagPrefix, false, []string{"frontend-masters/go-basics", "web-dev"}},
+		{"custom prefix", "#courses/fem", false, []string{"courses/fem/go-basics", "web-dev"}},
+		{"no prefix", "", false, []string{"go-basics", "web-dev"}},
+		{"section tags", DefaultTagPrefix, true, []string{"frontend-masters/go-basics/introduction", "web-dev"}},
+	}
+
+	course := testCourse()
+	position, _ := course.PositionOf(1)
+	lesson := course.Lessons["b"]
+
+	for _, c := range tagTests {
+		t.Run(c.name, func(t *testing.T) {
+			options := testOptions([]string{" #web dev"})
+			options.TagPrefix = c.prefix
+			options.SectionTags = c.sectionTags
+
+			markdown, err := NewMarkdownTemplater(course, outputdir.NewMemFS(), options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tags, _ := markdown.lessonContext(position, lesson).Frontmatter.Get("tags")
+			if !reflect.DeepEqual(tags, c.want) {
+				t.Errorf("got %v, want %v", tags, c.want)
+			}
+		})
+	}
+
+	if _, err := NewMarkdownTemplater(course, outputdir.NewMemFS(), testOptions([]string{"c++"})); err == nil {
+		t.Error("got no error for an invalid tag")
+	}
+}
+
+func TestMarkdownTemplater_ProgressView(t *testing.T) {
+	viewTests := []struct {
+		view string
+		name string
+		want string
+	}{
+		{BaseProgressView, "go-basics.base", "filters:\n  and:\n    - file.hasTag(\"frontend-masters/go-basics\")\n    - file.hasProperty(\"lesson_index\")\nviews:\n  - type: table\n    name: Progress\n"},
+		{DataviewProgressView, "go-basics-progress.md", "FROM #frontend-masters/go-basics\nWHERE lesson_index != null\nSORT lesson_index ASC\n"},
+	}
+
+	for _, c := range viewTests {
+		t.Run(c.view, func(t *testing.T) {
+			options := testOptions(nil)
+			options.ProgressView = c.view
+
+			memFS := outputdir.NewMemFS()
+			markdown, err := NewMarkdownTemplater(testCourse(), memFS, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := memFS.ReadFile(c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), c.want) {
+				t.Errorf("%s does not contain %q:\n%s", c.name, c.want, data)
+			}
+
+			courseNote, _ := memFS.ReadFile("go-basics.md")
+			if embed := "![[" + c.name + "]]"; !strings.Contains(string(courseNote), embed) {
+				t.Errorf("course note does not embed %s:\n%s", embed, courseNote)
+			}
+		})
+	}
+}
+
+func TestMarkdownTemplater_Flavor(t *testing.T) {
+	options := testOptions(nil)
+	options.Flavor = DefaultFlavor
+	if _, err := NewMarkdownTemplater(testCourse(), outputdir.NewMemFS(), options); err != nil {
+		t.Errorf("got %v for the default flavor", err)
+	}
+
+	options.Flavor = "notion"
+	if _, err := NewMarkdownTemplater(testCourse(), outputdir.NewMemFS(), options); err == nil {
+		t.Error("got no error for an unknown flavor")
+	}
+}
+
+func TestMarkdownTemplater_OneBased(t *testing.T) {
+	options := testOptions(nil)
+	options.Naming.OneBased = true
+
+	memFS := outputdir.NewMemFS()
+	markdown, err := NewMarkdownTemplater(testCourse(), memFS, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := memFS.ReadFile("1-introduction/2-setup.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"  - 2. Setup\n", "# 2. Setup\n", "lesson_index: 2\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("does not contain %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestAnnotationContext_Times(t *testing.T) {
+	timesTests := []struct {
+		name  string
+		Range []int
+		want  []string
+	}{
+		{"valid", []int{65, 70}, []string{"01:05", "01:10"}},
+		{"backwards", []int{70, 40}, []string{"01:10"}},
+		{"no end", []int{65}, []string{"01:05"}},
+		{"empty", nil, nil},
+		{"negative start", []int{-5, 10}, nil},
+	}
+
+	for _, c := range timesTests {
+		t.Run(c.name, func(t *testing.T) {
+			anno := AnnotationContext{AnnotationData: api.AnnotationData{Range: c.Range}, Format: api.TimestampClock}
+
+			if got := anno.Times(); !slices.Equal(got, c.want) {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+
+	t.Run("rendered", func(t *testing.T) {
+		course := testCourse()
+		lesson := course.Lessons["b"]
+		lesson.Annotations = api.Annotations{
+			{Range: []int{70, 40}, Message: "Backwards."},
+			{Message: "No range."},
+		}
+		course.Lessons["b"] = lesson
+
+		options := testOptions(nil)
+		options.BaseURL = "http://localhost:8080"
+		memFS := outputdir.NewMemFS()
+		markdown, err := NewMarkdownTemplater(course, memFS, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := markdown.GenerateCourseMarkdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		data, _ := memFS.ReadFile("0-introduction/1-setup.md")
+		for _, want := range []string{
+			"> [!NOTE]+ \n> No range.\n",
+			"> [!NOTE]+ [01:10](http://localhost:8080/courses/go-basics/setup/?t=70)\n> Backwards.\n",
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("does not contain %q:\n%s", want, data)
+			}
+		}
+	})
+}
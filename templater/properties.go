@@ -0,0 +1,127 @@
+package templater
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raphaeltannous/fem-helper/api"
+	"github.com/raphaeltannous/fem-helper/naming"
+	"github.com/raphaeltannous/fem-helper/yaml"
+)
+
+// Status every note starts with, to be changed as lessons are watched.
+const todoStatus = "todo"
+
+// Returns the typed properties of the course note, for Dataview and
+// Bases queries.
+func (markdown MarkdownTemplater) courseProperties() yaml.Map {
+	course := markdown.courseContext()
+
+	properties := yaml.Map{
+		{Key: "lesson_count", Value: markdown.course.LessonCount()},
+	}
+	if course.Duration > 0 {
+		properties.Set("duration_minutes", durationMinutes(course.Duration))
+	}
+	if !course.Published.IsZero() {
+		properties.Set("published", course.Published)
+	}
+	properties.Set("fem_url", course.URL)
+	properties.Set("status", todoStatus)
+
+	return properties
+}
+
+// Returns the typed properties of a lesson note, for Dataview and Bases
+// queries.
+func (markdown MarkdownTemplater) lessonProperties(lesson LessonContext) yaml.Map {
+	properties := yaml.Map{
+		{Key: "course", Value: wikilink(lesson.Course.Path, lesson.Course.Title)},
+		{Key: "section", Value: lesson.Section.Title},
+		{Key: "section_index", Value: lesson.Section.Number},
+		{Key: "lesson_index", Value: markdown.naming.Number(lesson.Index)},
+	}
+	if lesson.Duration > 0 {
+		properties.Set("duration_minutes", durationMinutes(lesson.Duration))
+	}
+	if !lesson.Course.Published.IsZero() {
+		properties.Set("published", lesson.Course.Published)
+	}
+	properties.Set("fem_url", lesson.WatchURL)
+	properties.Set("status", todoStatus)
+	properties.Set("annotation_count", len(lesson.Annotations.Process(api.ProcessOptions{Merge: markdown.annotations.Merge})))
+
+	return properties
+}
+
+// Returns duration in whole minutes, at least 1 for any positive duration.
+func durationMinutes(duration time.Duration) int {
+	return max(1, int(math.Round(duration.Minutes())))
+}
+
+// Kinds of progress views that can be generated next to the course note:
+// an Obsidian Bases file, or a note with Dataview queries.
+const (
+	BaseProgressView     = "base"
+	DataviewProgressView = "dataview"
+)
+
+var ProgressViews = []string{BaseProgressView, DataviewProgressView}
+
+// Returns the path of the progress view of kind, next to the course note.
+func progressViewPath(layout naming.Layout, kind string) string {
+	base := strings.TrimSuffix(layout.CourseFile, ".md")
+
+	switch kind {
+	case BaseProgressView:
+		return layout.Unique(base + ".base")
+	case DataviewProgressView:
+		return layout.Unique(base + "-progress.md")
+	}
+
+	return ""
+}
+
+// Returns the progress view of the course, listing its lessons by the
+// lesson properties.
+func (markdown MarkdownTemplater) formatProgressView() []byte {
+	courseTag := markdown.courseTag()
+
+	if markdown.progressView == BaseProgressView {
+		return []byte(yaml.Marshal(yaml.Map{
+			{Key: "filters", Value: yaml.Map{
+				{Key: "and", Value: []string{
+					fmt.Sprintf("file.hasTag(%s)", strconv.Quote(courseTag)),
+					`file.hasProperty("lesson_index")`,
+				}},
+			}},
+			{Key: "views", Value: []yaml.Map{{
+				{Key: "type", Value: "table"},
+				{Key: "name", Value: "Progress"},
+				{Key: "groupBy", Value: yaml.Map{
+					{Key: "property", Value: "section"},
+					{Key: "direction", Value: "ASC"},
+				}},
+				{Key: "order", Value: []string{"file.name", "duration_minutes", "annotation_count", "status"}},
+				{Key: "sort", Value: []yaml.Map{{
+					{Key: "property", Value: "lesson_index"},
+					{Key: "direction", Value: "ASC"},
+				}}},
+			}}},
+		}))
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "# %s progress\n\n", markdown.course.Title)
+	fmt.Fprintf(&result, "```dataview\n"+
+		"TABLE WITHOUT ID length(filter(rows.status, (status) => status = \"done\")) + \" of \" + length(rows) AS \"Done\", sum(rows.duration_minutes) AS \"Minutes\"\n"+
+		"FROM #%s\nWHERE lesson_index != null\nGROUP BY course\n```\n\n", courseTag)
+	fmt.Fprintf(&result, "```dataview\n"+
+		"TABLE WITHOUT ID file.link AS \"Lesson\", section AS \"Section\", duration_minutes AS \"Minutes\", status AS \"Status\"\n"+
+		"FROM #%s\nWHERE lesson_index != null\nSORT lesson_index ASC\n```\n", courseTag)
+
+	return []byte(result.String())
+}
@@ -0,0 +1,52 @@
+package templater
+
+import (
+	"github.com/raphaeltannous/fem-helper/tag"
+)
+
+// Prefix of the course tags, such as frontend-masters/<course slug>.
+const DefaultTagPrefix = "frontend-masters"
+
+// Normalizes tags into the course tags, and checks prefix.
+func (markdown *MarkdownTemplater) normalizeTags(tags []string, prefix string) error {
+	markdown.course.Tags = nil
+	for _, t := range tags {
+		normalized, err := tag.Normalize(t)
+		if err != nil {
+			return err
+		}
+
+		markdown.course.Tags = append(markdown.course.Tags, normalized)
+	}
+
+	if prefix != "" {
+		normalized, err := tag.Normalize(prefix)
+		if err != nil {
+			return err
+		}
+		prefix = normalized
+	}
+	markdown.tagPrefix = prefix
+
+	return nil
+}
+
+// Returns the tag of the course, <prefix>/<course slug>.
+func (markdown MarkdownTemplater) courseTag() string {
+	return tag.Join(markdown.tagPrefix, markdown.course.Slug)
+}
+
+// Returns the tag of the section at index x, <course tag>/<section slug>.
+func (markdown MarkdownTemplater) sectionTag(x int) string {
+	return tag.Join(markdown.courseTag(), markdown.layout.SectionSlugs[x])
+}
+
+// Returns the tag of the notes of the section at index x: the section tag
+// with section tags, and the course tag otherwise.
+func (markdown MarkdownTemplater) noteTag(x int) string {
+	if markdown.sectionTags {
+		return markdown.sectionTag(x)
+	}
+
+	return markdown.courseTag()
+}
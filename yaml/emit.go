@@ -0,0 +1,209 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Map is a YAML mapping that keeps the order of its keys.
+type Map []Item
+
+// Item is a key of a Map and its value.
+type Item struct {
+	Key   string
+	Value any
+}
+
+// Returns the value of key, and whether m has it.
+func (m Map) Get(key string) (any, bool) {
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Sets key to value, in place if m already has key and last otherwise.
+func (m *Map) Set(key string, value any) {
+	for x, item := range *m {
+		if item.Key == key {
+			(*m)[x].Value = value
+			return
+		}
+	}
+
+	*m = append(*m, Item{key, value})
+}
+
+// Returns a copy of m with key set to value.
+func (m Map) With(key string, value any) Map {
+	with := slices.Clone(m)
+	with.Set(key, value)
+
+	return with
+}
+
+// Returns a copy of m without key.
+func (m Map) Without(key string) Map {
+	return slices.DeleteFunc(slices.Clone(m), func(item Item) bool {
+		return item.Key == key
+	})
+}
+
+// Returns value as a YAML document in block style, ending with a newline.
+//
+// Map and map values are written as mappings, map keys being sorted,
+// slices and arrays as sequences, and every other value as a Scalar.
+func Marshal(value any) string {
+	var result strings.Builder
+	writeValue(&result, normalize(value), 0)
+
+	return result.String()
+}
+
+// Returns value as a Map, a []any or a scalar.
+func normalize(value any) any {
+	switch value := value.(type) {
+	case nil, string, time.Time, time.Duration, fmt.Stringer:
+		return value
+	case Map:
+		normalized := make(Map, len(value))
+		for x, item := range value {
+			normalized[x] = Item{item.Key, normalize(item.Value)}
+		}
+		return normalized
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Map:
+		var normalized Map
+		for _, key := range reflected.MapKeys() {
+			normalized = append(normalized, Item{fmt.Sprint(key.Interface()), normalize(reflected.MapIndex(key).Interface())})
+		}
+		slices.SortFunc(normalized, func(a, b Item) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+		return normalized
+	case reflect.Slice, reflect.Array:
+		if reflected.Kind() == reflect.Slice && reflected.IsNil() {
+			return []any{}
+		}
+		normalized := make([]any, reflected.Len())
+		for x := range normalized {
+			normalized[x] = normalize(reflected.Index(x).Interface())
+		}
+		return normalized
+	case reflect.Pointer, reflect.Interface:
+		if reflected.IsNil() {
+			return nil
+		}
+		return normalize(reflected.Elem().Interface())
+	}
+
+	return value
+}
+
+// Writes a normalized value indented by depth levels.
+func writeValue(result *strings.Builder, value any, depth int) {
+	padding := strings.Repeat("  ", depth)
+
+	switch value := value.(type) {
+	case Map:
+		if len(value) == 0 {
+			result.WriteString(padding + "{}\n")
+			return
+		}
+
+		for _, item := range value {
+			result.WriteString(padding + String(item.Key) + ":")
+			writeNested(result, item.Value, depth)
+		}
+	case []any:
+		if len(value) == 0 {
+			result.WriteString(padding + "[]\n")
+			return
+		}
+
+		for _, element := range value {
+			result.WriteString(padding + "-")
+			writeSequenceElement(result, element, depth)
+		}
+	default:
+		result.WriteString(padding + Scalar(value) + "\n")
+	}
+}
+
+// Writes the value of a mapping key, after its colon. Sequences are
+// indented under their key, as Obsidian writes them.
+func writeNested(result *strings.Builder, value any, depth int) {
+	switch nested := value.(type) {
+	case Map:
+		if len(nested) == 0 {
+			result.WriteString(" {}\n")
+			return
+		}
+		result.WriteString("\n")
+		writeValue(result, nested, depth+1)
+	case []any:
+		if len(nested) == 0 {
+			result.WriteString(" []\n")
+			return
+		}
+		result.WriteString("\n")
+		writeValue(result, nested, depth+1)
+	default:
+		result.WriteString(" " + Scalar(value) + "\n")
+	}
+}
+
+// Writes an element of a sequence, after its dash.
+func writeSequenceElement(result *strings.Builder, value any, depth int) {
+	switch nested := value.(type) {
+	case Map, []any:
+		var element strings.Builder
+		writeValue(&element, nested, depth+1)
+
+		// The first line of the element goes on the line of the dash.
+		text := strings.TrimPrefix(element.String(), strings.Repeat("  ", depth+1))
+		result.WriteString(" " + text)
+	default:
+		result.WriteString(" " + Scalar(value) + "\n")
+	}
+}
+
+// Returns the value a plain or double quoted scalar text stands for:
+// booleans, integers, floats and null are converted, and everything else
+// is kept as a string.
+func ParseScalar(text string) any {
+	text = strings.TrimSpace(text)
+
+	if unquoted, err := strconv.Unquote(text); err == nil && strings.HasPrefix(text, `"`) {
+		return unquoted
+	}
+
+	switch strings.ToLower(text) {
+	case "", "null", "~":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if number, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return number
+	}
+
+	if number, err := strconv.ParseFloat(text, 64); err == nil {
+		return number
+	}
+
+	return text
+}
@@ -0,0 +1,86 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	marshalTests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			"frontmatter",
+			Map{
+				{"aliases", []string{`0. Say "Hi"`}},
+				{"tags", []string{"frontend-masters/go", "c#"}},
+				{"lesson_index", 3},
+				{"title", "Vue: The Basics"},
+				{"empty", []string{}},
+			},
+			"aliases:\n  - 0. Say \"Hi\"\ntags:\n  - frontend-masters/go\n  - c#\nlesson_index: 3\ntitle: \"Vue: The Basics\"\nempty: []\n",
+		},
+		{
+			"nested",
+			map[string]any{
+				"b": map[string]int{"y": 2, "x": 1},
+				"a": []any{Map{{"k", "v"}, {"l", "w"}}, []int{1}},
+			},
+			"a:\n  - k: v\n    l: w\n  - - 1\nb:\n  x: 1\n  \"y\": 2\n",
+		},
+		{"scalar", "yes", "\"yes\"\n"},
+		{"empty map", Map{}, "{}\n"},
+	}
+
+	for _, c := range marshalTests {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Marshal(c.value); got != c.want {
+				t.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := Map{{"a", 1}}
+	m.Set("b", 2)
+	m.Set("a", 3)
+
+	want := Map{{"a", 3}, {"b", 2}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v, want %v", m, want)
+	}
+
+	if with := m.With("c", 4); len(m) != 2 || len(with) != 3 {
+		t.Errorf("With changed the map: %v", m)
+	}
+
+	if without := m.Without("a"); !reflect.DeepEqual(without, Map{{"b", 2}}) || len(m) != 2 {
+		t.Errorf("got %v, want only b", without)
+	}
+}
+
+func TestParseScalar(t *testing.T) {
+	parseTests := []struct {
+		text string
+		want any
+	}{
+		{"true", true},
+		{"42", int64(42)},
+		{"1.5", 1.5},
+		{"null", nil},
+		{"todo", "todo"},
+		{`"42"`, "42"},
+		{"Vue: The Basics", "Vue: The Basics"},
+	}
+
+	for _, c := range parseTests {
+		t.Run(c.text, func(t *testing.T) {
+			if got := ParseScalar(c.text); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %#v, want %#v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Package yaml writes YAML values, as used in note frontmatter.
+package yaml
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Returns value as a YAML scalar. Strings are left plain when they can
+// not be read back as anything else, and double quoted otherwise.
+//
+// time.Time values are written as dates when they have no time of day,
+// and in RFC 3339 otherwise. Values that are not scalars are written as
+// their fmt string.
+func Scalar(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return String(value)
+	case time.Time:
+		if value.IsZero() {
+			return "null"
+		}
+		if value.Equal(value.Truncate(24 * time.Hour)) {
+			return value.Format(time.DateOnly)
+		}
+		return value.Format(time.RFC3339)
+	case time.Duration:
+		return String(value.String())
+	case fmt.Stringer:
+		return String(value.String())
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(reflected.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(reflected.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(reflected.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return float(reflected.Float())
+	case reflect.String:
+		return String(reflected.String())
+	case reflect.Pointer, reflect.Interface:
+		if reflected.IsNil() {
+			return "null"
+		}
+		return Scalar(reflected.Elem().Interface())
+	}
+
+	return String(fmt.Sprint(value))
+}
+
+func float(number float64) string {
+	switch {
+	case math.IsNaN(number):
+		return ".nan"
+	case math.IsInf(number, 1):
+		return ".inf"
+	case math.IsInf(number, -1):
+		return "-.inf"
+	}
+
+	return strconv.FormatFloat(number, 'g', -1, 64)
+}
+
+// Returns text as a YAML string scalar, double quoted if it would not be
+// read back as the same string when left plain.
+func String(text string) string {
+	if isPlain(text) {
+		return text
+	}
+
+	return Quote(text)
+}
+
+// Returns text as a double quoted YAML string.
+func Quote(text string) string {
+	var result strings.Builder
+
+	result.WriteByte('"')
+	for _, char := range text {
+		switch char {
+		case '"':
+			result.WriteString(`\"`)
+		case '\\':
+			result.WriteString(`\\`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\r':
+			result.WriteString(`\r`)
+		default:
+			switch {
+			case char < 0x20 || char == 0x7f:
+				fmt.Fprintf(&result, `\x%02X`, char)
+			case !unicode.IsPrint(char) && char <= 0xffff:
+				fmt.Fprintf(&result, `\u%04X`, char)
+			case !unicode.IsPrint(char):
+				fmt.Fprintf(&result, `\U%08X`, char)
+			default:
+				result.WriteRune(char)
+			}
+		}
+	}
+	result.WriteByte('"')
+
+	return result.String()
+}
+
+// Plain scalars that are read back as booleans or null by YAML 1.1 or 1.2
+// readers.
+var reserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+// Plain scalars that are read back as timestamps, the timestamp pattern of
+// the YAML 1.1 type repository.
+var timestampPattern = regexp.MustCompile(`^(?:[0-9]{4}-[0-9]{2}-[0-9]{2}|` +
+	`[0-9]{4}-[0-9]{1,2}-[0-9]{1,2}(?:[Tt]|[ \t]+)[0-9]{1,2}:[0-9]{2}:[0-9]{2}(?:\.[0-9]*)?` +
+	`(?:[ \t]*(?:Z|[-+][0-9]{1,2}(?::[0-9]{2})?))?)$`)
+
+// Returns whether text can be written as a plain scalar.
+func isPlain(text string) bool {
+	if text == "" || text != strings.TrimSpace(text) {
+		return false
+	}
+
+	if reserved[strings.ToLower(text)] || looksNumeric(text) || timestampPattern.MatchString(text) {
+		return false
+	}
+
+	// Indicators that start something else than a plain scalar.
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(text[0])) {
+		// "-", "?" and ":" only do when followed by a space.
+		if !strings.ContainsRune("-?:", rune(text[0])) || len(text) == 1 || text[1] == ' ' {
+			return false
+		}
+	}
+
+	if strings.Contains(text, ": ") || strings.Contains(text, " #") || strings.HasSuffix(text, ":") {
+		return false
+	}
+
+	// Flow indicators are only safe outside of flow collections, quoting
+	// them keeps scalars valid in both.
+	if strings.ContainsAny(text, ",[]{}") {
+		return false
+	}
+
+	for _, char := range text {
+		if !unicode.IsPrint(char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns whether a plain text would be read back as a number.
+func looksNumeric(text string) bool {
+	trimmed := strings.TrimLeft(text, "+-")
+	switch strings.ToLower(trimmed) {
+	case ".inf", ".nan":
+		return true
+	}
+
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64); err == nil {
+		return true
+	}
+
+	if _, err := strconv.ParseInt(strings.ReplaceAll(text, "_", ""), 0, 64); err == nil {
+		return true
+	}
+
+	// Sexagesimal numbers of YAML 1.1, such as 1:30.
+	if strings.Contains(trimmed, ":") && strings.Trim(trimmed, "0123456789:.") == "" {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package yaml
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScalar(t *testing.T) {
+	scalarTests := []struct {
+		value any
+		want  string
+	}{
+		{nil, "null"},
+		{true, "true"},
+		{42, "42"},
+		{1.5, "1.5"},
+		{"Go Basics", "Go Basics"},
+		{"", `""`},
+		{"yes", `"yes"`},
+		{"Null", `"Null"`},
+		{"123", `"123"`},
+		{"0x1F", `"0x1F"`},
+		{"1:30", `"1:30"`},
+		{"1.0.2", "1.0.2"},
+		{"2024-01-01", `"2024-01-01"`},
+		{"2024-01-01T10:00:00Z", `"2024-01-01T10:00:00Z"`},
+		{"2024-1-1 10:00:00.5 +02:00", `"2024-1-1 10:00:00.5 +02:00"`},
+		{"2024-01-01 release", "2024-01-01 release"},
+		{"Vue: The Basics", `"Vue: The Basics"`},
+		{"C# for you", "C# for you"},
+		{"Go #1", `"Go #1"`},
+		{"#tag", `"#tag"`},
+		{"- item", `"- item"`},
+		{"-item", "-item"},
+		{"a, b", `"a, b"`},
+		{` padded `, `" padded "`},
+		{`say "hi"`, `say "hi"`},
+		{`"quoted"`, `"\"quoted\""`},
+		{"line\nbreak", `"line\nbreak"`},
+		{`back\slash`, `back\slash`},
+		{"tab\there", `"tab\there"`},
+		{"bell\a", `"bell\x07"`},
+		{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "2024-01-02"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02T03:04:05Z"},
+		{time.Time{}, "null"},
+		{90 * time.Second, "1m30s"},
+	}
+
+	for _, c := range scalarTests {
+		t.Run(c.want, func(t *testing.T) {
+			if got := Scalar(c.value); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}